@@ -0,0 +1,72 @@
+package taurosapi
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+)
+
+// Logger - destination of debug output, satisfied by *log.Logger
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// WithDebugLogger - log the (redacted) signing details of every signed request to l
+func WithDebugLogger(l Logger) Option {
+	return func(cfg *clientConfig) { cfg.debugLogger = l }
+}
+
+// signatureDebug holds what is needed to diagnose a rejected signature
+// without exposing the api secret
+type signatureDebug struct {
+	Nonce             string
+	Message           string
+	MessageHash       [32]byte
+	Redacted          bool // credentials were removed from Message, MessageHash is not shown
+	SecretFingerprint string
+}
+
+// redactedFields matches credentials that may travel in a signed body
+var redactedFields = regexp.MustCompile(`"(nip|password)"\s*:\s*"[^"]*"`)
+
+func newSignatureDebug(nonce, message string, messageHash [32]byte, secretFingerprint string) *signatureDebug {
+	redacted := redactedFields.ReplaceAllString(message, `"$1":"[REDACTED]"`)
+	d := &signatureDebug{
+		Nonce:             nonce,
+		Message:           redacted,
+		Redacted:          redacted != message,
+		SecretFingerprint: secretFingerprint,
+	}
+	if !d.Redacted { //the hash of a redacted message would allow brute forcing a short nip
+		d.MessageHash = messageHash
+	}
+	return d
+}
+
+// String - full dump, only written to the debug logger
+func (d *signatureDebug) String() string {
+	if d == nil {
+		return ""
+	}
+	hash := fmt.Sprintf("%x", d.MessageHash)
+	if d.Redacted {
+		hash = "omitted, message redacted"
+	}
+	return fmt.Sprintf("\nnonce=%s\nmessage: %s\nmessageHash: %s\nsecret fingerprint: %s",
+		d.Nonce, d.Message, hash, d.SecretFingerprint)
+}
+
+// short - nonce and secret fingerprint, appended to the error of a rejected signature
+func (d *signatureDebug) short() string {
+	if d == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (nonce=%s, secret fingerprint: %s)", d.Nonce, d.SecretFingerprint)
+}
+
+// SecretFingerprint - short non reversible identifier of a decoded api secret,
+// safe to log to tell which secret signed a request
+func SecretFingerprint(decodedSecret []byte) string {
+	sum := sha256.Sum256(decodedSecret)
+	return fmt.Sprintf("%x", sum[:4])
+}
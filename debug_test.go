@@ -0,0 +1,58 @@
+package taurosapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSignatureDebugRedacted(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString([]byte("supersecret"))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"success":false,"msg":"Invalid signature"}`))
+	}))
+	defer srv.Close()
+	var logged bytes.Buffer
+	c, _ := NewClient("key", secret, WithBaseURL(srv.URL), WithDebugLogger(log.New(&logged, "", 0)))
+	_, err := c.Transfer(TransferMsg{Nip: "123456", Coin: "MXN", Recipient: "a@b.c", Amount: NewDecimal(1, 0)})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, out := range []string{err.Error(), logged.String()} {
+		if !strings.Contains(out, "secret fingerprint: "+SecretFingerprint([]byte("supersecret"))) {
+			t.Errorf("missing fingerprint in %q", out)
+		}
+		if !strings.Contains(out, "nonce=") {
+			t.Errorf("missing nonce in %q", out)
+		}
+		for _, leak := range []string{secret, "supersecret", "123456"} {
+			if strings.Contains(out, leak) {
+				t.Errorf("debug output leaks %q: %q", leak, out)
+			}
+		}
+	}
+	if strings.Contains(err.Error(), "message:") {
+		t.Errorf("signed message in the error without a debug logger: %q", err)
+	}
+	if !strings.Contains(logged.String(), "messageHash: omitted") {
+		t.Errorf("hash of a redacted message logged: %q", logged.String())
+	}
+}
+
+func TestSignatureDebugHash(t *testing.T) {
+	var hash [32]byte
+	hash[0] = 0xab
+	d := newSignatureDebug("1", `{"coin":"MXN"}`, hash, "fp")
+	if d.Redacted || !strings.Contains(d.String(), "messageHash: ab00") {
+		t.Errorf("hash of a message without credentials not shown: %s", d)
+	}
+	d = newSignatureDebug("1", `{"nip":"123456"}`, hash, "fp")
+	if !d.Redacted || d.MessageHash != [32]byte{} || strings.Contains(d.String(), "ab00") {
+		t.Errorf("hash of a redacted message kept: %s", d)
+	}
+}
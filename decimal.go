@@ -0,0 +1,279 @@
+package taurosapi
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Decimal - exact fixed-point number used for every amount, price and fee
+//
+// The value is coef * 10^-scale. The zero value is 0 and every operation
+// returns a new Decimal, so values can be copied and shared freely.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+// maxDecimalScale bounds the exponent and the scale accepted by ParseDecimal,
+// so input from the network cannot make it build huge numbers
+const maxDecimalScale = 1000
+
+var decimalPattern = regexp.MustCompile(`^([+-]?)(\d*)(?:\.(\d*))?(?:[eE]([+-]?\d+))?$`)
+
+// NewDecimal - decimal with value unscaled * 10^-scale, e.g. NewDecimal(199, 2) is 1.99
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{coef: new(big.Int).Mul(big.NewInt(unscaled), pow10(-scale))}
+	}
+	return Decimal{coef: big.NewInt(unscaled), scale: scale}
+}
+
+// ParseDecimal - parse a decimal like "0.00125", "-12" or "1e-8"
+func ParseDecimal(s string) (Decimal, error) {
+	m := decimalPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil || m[2]+m[3] == "" {
+		return Decimal{}, fmt.Errorf("ParseDecimal-> invalid decimal %q", s)
+	}
+	coef, _ := new(big.Int).SetString(m[2]+m[3], 10)
+	if m[1] == "-" {
+		coef.Neg(coef)
+	}
+	scale := int64(len(m[3]))
+	if m[4] != "" {
+		exp, err := strconv.ParseInt(m[4], 10, 32)
+		if err != nil || exp > maxDecimalScale || exp < -maxDecimalScale {
+			return Decimal{}, fmt.Errorf("ParseDecimal-> exponent out of range in %q", s)
+		}
+		scale -= exp
+	}
+	if scale > maxDecimalScale || scale < -maxDecimalScale {
+		return Decimal{}, fmt.Errorf("ParseDecimal-> scale out of range in %q", s)
+	}
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+// MustDecimal - like ParseDecimal but panics on invalid input, for constants
+func MustDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromFloat - shortest decimal representation of f
+func DecimalFromFloat(f float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		return Decimal{}
+	}
+	return d
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) bigCoef() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale returns the coefficient of d expressed with the given (larger or equal) scale
+func (d Decimal) rescale(scale int32) *big.Int {
+	c := new(big.Int).Set(d.bigCoef())
+	if scale > d.scale {
+		c.Mul(c, pow10(scale-d.scale))
+	}
+	return c
+}
+
+func maxScale(a, b Decimal) int32 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
+
+// Add - d + e
+func (d Decimal) Add(e Decimal) Decimal {
+	s := maxScale(d, e)
+	return Decimal{coef: new(big.Int).Add(d.rescale(s), e.rescale(s)), scale: s}
+}
+
+// Sub - d - e
+func (d Decimal) Sub(e Decimal) Decimal {
+	s := maxScale(d, e)
+	return Decimal{coef: new(big.Int).Sub(d.rescale(s), e.rescale(s)), scale: s}
+}
+
+// Mul - d * e, exact
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.bigCoef(), e.bigCoef()), scale: d.scale + e.scale}
+}
+
+// Div - d / e rounded half away from zero to the given number of decimal places, panics if e is zero
+func (d Decimal) Div(e Decimal, places int32) Decimal {
+	if e.IsZero() {
+		panic("taurosapi: decimal division by zero")
+	}
+	if places < 0 {
+		places = 0
+	}
+	// d/e = dc/ec * 10^(es-ds), computed with one extra digit for rounding
+	num := new(big.Int).Set(d.bigCoef())
+	den := new(big.Int).Set(e.bigCoef())
+	if shift := int64(places) + 1 + int64(e.scale) - int64(d.scale); shift >= 0 {
+		num.Mul(num, pow10(int32(shift)))
+	} else {
+		den.Mul(den, pow10(int32(-shift)))
+	}
+	return Decimal{coef: num.Quo(num, den), scale: places + 1}.Round(places)
+}
+
+// Neg - -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.bigCoef()), scale: d.scale}
+}
+
+// Abs - |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.bigCoef()), scale: d.scale}
+}
+
+// Sign - -1, 0 or +1 according to the sign of d
+func (d Decimal) Sign() int {
+	return d.bigCoef().Sign()
+}
+
+// IsZero - d == 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp - -1, 0 or +1 if d is lower, equal or greater than e
+func (d Decimal) Cmp(e Decimal) int {
+	s := maxScale(d, e)
+	return d.rescale(s).Cmp(e.rescale(s))
+}
+
+// Equal - d == e regardless of their scale, i.e. 1.50 equals 1.5
+func (d Decimal) Equal(e Decimal) bool {
+	return d.Cmp(e) == 0
+}
+
+// LessThan - d < e
+func (d Decimal) LessThan(e Decimal) bool {
+	return d.Cmp(e) < 0
+}
+
+// GreaterThan - d > e
+func (d Decimal) GreaterThan(e Decimal) bool {
+	return d.Cmp(e) > 0
+}
+
+// MinDecimal - the lowest of a and b
+func MinDecimal(a, b Decimal) Decimal {
+	if b.LessThan(a) {
+		return b
+	}
+	return a
+}
+
+// MaxDecimal - the greatest of a and b
+func MaxDecimal(a, b Decimal) Decimal {
+	if b.GreaterThan(a) {
+		return b
+	}
+	return a
+}
+
+// Scale - number of decimal places of d
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Round - d rounded half away from zero to the given decimal places, e.g. the precision of a market
+func (d Decimal) Round(places int32) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return d
+	}
+	div := pow10(d.scale - places)
+	q, r := new(big.Int).QuoRem(d.bigCoef(), div, new(big.Int))
+	r.Abs(r).Lsh(r, 1)
+	if r.Cmp(div) >= 0 {
+		q.Add(q, big.NewInt(int64(d.Sign())))
+	}
+	return Decimal{coef: q, scale: places}
+}
+
+// Truncate - d rounded toward zero to the given decimal places, so an amount never exceeds what is available
+func (d Decimal) Truncate(places int32) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return d
+	}
+	return Decimal{coef: new(big.Int).Quo(d.bigCoef(), pow10(d.scale-places)), scale: places}
+}
+
+// Float64 - nearest float64 to d, for display or statistics only
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String - d in plain notation keeping its scale, e.g. "0.00100000"
+func (d Decimal) String() string {
+	c := d.bigCoef()
+	digits := new(big.Int).Abs(c).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if c.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalJSON - encode d as a quoted string so no precision is lost
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON - accept quoted and bare numbers, an empty string or null decode as zero
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	s := string(bytes.Trim(data, `"`))
+	if s == "" {
+		*d = Decimal{}
+		return nil
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
@@ -0,0 +1,109 @@
+package taurosapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := map[string]string{
+		"0.00125":  "0.00125",
+		"-12":      "-12",
+		"+.5":      "0.5",
+		"1e-8":     "0.00000001",
+		"2.5E3":    "2500",
+		"250000.0": "250000.0",
+		"1e1000":   "1" + strings.Repeat("0", 1000),
+	}
+	for in, want := range tests {
+		d, err := ParseDecimal(in)
+		if err != nil {
+			t.Errorf("%s: %v", in, err)
+			continue
+		}
+		if d.String() != want {
+			t.Errorf("%s: got %s, want %s", in, d, want)
+		}
+	}
+	for _, in := range []string{"", "abc", "1.2.3", ".", "1e100000000", "1e-2147483648", "5e-2147483647", "1e1001", "0." + strings.Repeat("0", 1001)} {
+		if _, err := ParseDecimal(in); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := MustDecimal("0.1"), MustDecimal("0.2")
+	if sum := a.Add(b); !sum.Equal(MustDecimal("0.3")) {
+		t.Errorf("0.1 + 0.2 = %s", sum)
+	}
+	if diff := a.Sub(b); diff.String() != "-0.1" {
+		t.Errorf("0.1 - 0.2 = %s", diff)
+	}
+	if prod := MustDecimal("1.5").Mul(MustDecimal("0.003")); prod.String() != "0.0045" {
+		t.Errorf("1.5 * 0.003 = %s", prod)
+	}
+	if q := MustDecimal("1").Div(MustDecimal("3"), 8); q.String() != "0.33333333" {
+		t.Errorf("1 / 3 = %s", q)
+	}
+	if q := MustDecimal("2").Div(MustDecimal("3"), 2); q.String() != "0.67" {
+		t.Errorf("2 / 3 = %s", q)
+	}
+	if q := MustDecimal("-2").Div(MustDecimal("3"), 2); q.String() != "-0.67" {
+		t.Errorf("-2 / 3 = %s", q)
+	}
+	if !MustDecimal("1.50").Equal(MustDecimal("1.5")) || !a.LessThan(b) || a.Cmp(b) != -1 {
+		t.Error("unexpected comparison")
+	}
+	var zero Decimal
+	if !zero.IsZero() || zero.String() != "0" || !zero.Add(a).Equal(a) {
+		t.Error("zero value is not usable")
+	}
+}
+
+func TestDecimalRounding(t *testing.T) {
+	d := MustDecimal("1.23456789")
+	if r := d.Round(4); r.String() != "1.2346" {
+		t.Errorf("Round(4) = %s", r)
+	}
+	if r := d.Truncate(4); r.String() != "1.2345" {
+		t.Errorf("Truncate(4) = %s", r)
+	}
+	if r := MustDecimal("-0.005").Round(2); r.String() != "-0.01" {
+		t.Errorf("Round(-0.005, 2) = %s", r)
+	}
+	if r := d.Round(10); r.String() != d.String() {
+		t.Errorf("Round beyond scale changed value: %s", r)
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var v struct {
+		A Decimal `json:"a"`
+		B Decimal `json:"b"`
+		C Decimal `json:"c"`
+		D Decimal `json:"d"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":"0.00012345","b":1.99,"c":"","d":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A.String() != "0.00012345" || v.B.String() != "1.99" || !v.C.IsZero() || !v.D.IsZero() {
+		t.Errorf("unexpected decode %+v", v)
+	}
+	out, _ := json.Marshal(v)
+	if string(out) != `{"a":"0.00012345","b":"1.99","c":"0","d":"0"}` {
+		t.Errorf("unexpected encode %s", out)
+	}
+}
+
+func TestNewOrderOmitsZeroPrice(t *testing.T) {
+	out, _ := json.Marshal(NewOrder{Market: "BTC-MXN", Side: "buy", Amount: MustDecimal("0.001"), Type: "market"})
+	if string(out) != `{"market":"btc-mxn","side":"buy","amount":"0.001","type":"market"}` {
+		t.Errorf("unexpected market order %s", out)
+	}
+	out, _ = json.Marshal(NewOrder{Market: "BTC-MXN", Side: "sell", Amount: MustDecimal("0.001"), Type: "limit", Price: MustDecimal("250000")})
+	if string(out) != `{"market":"btc-mxn","side":"sell","amount":"0.001","type":"limit","price":"250000"}` {
+		t.Errorf("unexpected limit order %s", out)
+	}
+}
@@ -0,0 +1,237 @@
+package taurosapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Side - side of an order
+type Side string
+
+// Order sides
+const (
+	SideBuy  Side = "buy"
+	SideSell Side = "sell"
+)
+
+// ParseSide - parse "buy" or "sell", case insensitive
+func ParseSide(s string) (Side, error) {
+	side := Side(strings.ToLower(strings.TrimSpace(s)))
+	if err := side.Validate(); err != nil {
+		return "", err
+	}
+	return side, nil
+}
+
+// Validate - check that the side is SideBuy or SideSell
+func (s Side) Validate() error {
+	switch s {
+	case SideBuy, SideSell:
+		return nil
+	}
+	return fmt.Errorf("invalid order side %q", string(s))
+}
+
+// OrderType - type of an order
+type OrderType string
+
+// Order types
+const (
+	OrderTypeLimit  OrderType = "limit"
+	OrderTypeMarket OrderType = "market"
+)
+
+// ParseOrderType - parse "limit" or "market", case insensitive
+func ParseOrderType(s string) (OrderType, error) {
+	ot := OrderType(strings.ToLower(strings.TrimSpace(s)))
+	if err := ot.Validate(); err != nil {
+		return "", err
+	}
+	return ot, nil
+}
+
+// Validate - check that the type is OrderTypeLimit or OrderTypeMarket
+func (ot OrderType) Validate() error {
+	switch ot {
+	case OrderTypeLimit, OrderTypeMarket:
+		return nil
+	}
+	return fmt.Errorf("invalid order type %q", string(ot))
+}
+
+// OrderStatus - lifecycle state of an order
+type OrderStatus string
+
+// Order statuses
+const (
+	OrderStatusOpen      OrderStatus = "open"      // on the book, nothing filled yet
+	OrderStatusPartial   OrderStatus = "partial"   // on the book, partially filled
+	OrderStatusFilled    OrderStatus = "filled"    // closed, completely filled
+	OrderStatusCancelled OrderStatus = "cancelled" // closed before being completely filled, Filled tells how much was
+
+	// OrderStatusUnknown is set on orders sent without a status nor enough
+	// fields to infer it; fetch the order with GetOrder to learn its state
+	OrderStatusUnknown OrderStatus = "unknown"
+)
+
+// ParseOrderStatus - parse a status, accepting the spellings used by Tauros ("canceled", "partially_filled", ...)
+func ParseOrderStatus(s string) (OrderStatus, error) {
+	status := OrderStatus(strings.ToLower(strings.TrimSpace(s)))
+	switch status {
+	case "canceled":
+		status = OrderStatusCancelled
+	case "partially_filled", "partial_filled", "partially filled":
+		status = OrderStatusPartial
+	case "completed":
+		status = OrderStatusFilled
+	}
+	if err := status.Validate(); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// Validate - check that the status is one of the OrderStatus constants sent by Tauros, i.e. not OrderStatusUnknown
+func (s OrderStatus) Validate() error {
+	switch s {
+	case OrderStatusOpen, OrderStatusPartial, OrderStatusFilled, OrderStatusCancelled:
+		return nil
+	}
+	return fmt.Errorf("invalid order status %q", string(s))
+}
+
+// UnmarshalJSON - normalise the status spellings of Tauros, keeping unknown values as sent
+func (s *OrderStatus) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if status, err := ParseOrderStatus(raw); err == nil {
+		*s = status
+	} else {
+		*s = OrderStatus(raw)
+	}
+	return nil
+}
+
+// Liquidity - role of an order in a trade
+type Liquidity string
+
+// Liquidity roles
+const (
+	LiquidityMaker Liquidity = "maker" // the order was resting on the book
+	LiquidityTaker Liquidity = "taker" // the order matched a resting one
+)
+
+// Symbol - market symbol in its normalised form BASE-QUOTE, e.g. "BTC-MXN"
+type Symbol string
+
+// NewSymbol - symbol of the market trading base against quote
+func NewSymbol(base, quote string) Symbol {
+	return Symbol(strings.ToUpper(base) + "-" + strings.ToUpper(quote))
+}
+
+// ParseSymbol - parse and normalise a market symbol, accepting "btc-mxn", "BTC_MXN" or "btc/mxn"
+func ParseSymbol(s string) (Symbol, error) {
+	sym := Symbol(strings.ToUpper(strings.NewReplacer("_", "-", "/", "-").Replace(strings.TrimSpace(s))))
+	if err := sym.Validate(); err != nil {
+		return "", err
+	}
+	return sym, nil
+}
+
+// Validate - check that the symbol is in its normalised form BASE-QUOTE
+func (s Symbol) Validate() error {
+	parts := strings.Split(string(s), "-")
+	if len(parts) != 2 || parts[0] == parts[1] || !isCoinCode(parts[0]) || !isCoinCode(parts[1]) {
+		return fmt.Errorf("invalid market symbol %q", string(s))
+	}
+	return nil
+}
+
+func isCoinCode(c string) bool {
+	if c == "" {
+		return false
+	}
+	for _, r := range c {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Base - coin being traded, e.g. "BTC" for "BTC-MXN"
+func (s Symbol) Base() string {
+	if i := strings.Index(string(s), "-"); i >= 0 {
+		return string(s)[:i]
+	}
+	return string(s)
+}
+
+// Quote - coin the price is expressed in, e.g. "MXN" for "BTC-MXN"
+func (s Symbol) Quote() string {
+	if i := strings.Index(string(s), "-"); i >= 0 {
+		return string(s)[i+1:]
+	}
+	return ""
+}
+
+// param is the form Tauros expects in requests, e.g. "btc-mxn"
+func (s Symbol) param() string {
+	return strings.ToLower(string(s))
+}
+
+// UnmarshalJSON - normalise the symbols sent by Tauros, keeping unparseable ones as they are
+func (s *Symbol) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if sym, err := ParseSymbol(raw); err == nil {
+		*s = sym
+	} else {
+		*s = Symbol(raw)
+	}
+	return nil
+}
+
+// normalize parses the market, side and type of the order, so "btc_mxn",
+// "BUY" or " Limit" are accepted; values that do not parse are left for Validate
+func (o *NewOrder) normalize() {
+	if market, err := ParseSymbol(string(o.Market)); err == nil {
+		o.Market = market
+	}
+	if side, err := ParseSide(string(o.Side)); err == nil {
+		o.Side = side
+	}
+	if ot, err := ParseOrderType(string(o.Type)); err == nil {
+		o.Type = ot
+	}
+}
+
+// Validate - check the order before it is signed and sent
+func (o NewOrder) Validate() error {
+	var errs []string
+	if err := o.Market.Validate(); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := o.Side.Validate(); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := o.Type.Validate(); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if o.Amount.Sign() <= 0 {
+		errs = append(errs, "amount must be positive")
+	}
+	if o.Type == OrderTypeLimit && o.Price.Sign() <= 0 {
+		errs = append(errs, "limit orders need a positive price")
+	}
+	if len(errs) > 0 {
+		return errors.New("invalid order: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
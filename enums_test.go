@@ -0,0 +1,97 @@
+package taurosapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestParseSymbol(t *testing.T) {
+	for _, in := range []string{"btc-mxn", "BTC_MXN", "btc/mxn", " BTC-MXN "} {
+		sym, err := ParseSymbol(in)
+		if err != nil {
+			t.Errorf("%q: %v", in, err)
+			continue
+		}
+		if sym != "BTC-MXN" || sym.Base() != "BTC" || sym.Quote() != "MXN" {
+			t.Errorf("%q: got %s (%s/%s)", in, sym, sym.Base(), sym.Quote())
+		}
+	}
+	for _, in := range []string{"", "btcmxn", "btc-", "btc-btc", "btc-mxn-usd", "bt c-mxn"} {
+		if _, err := ParseSymbol(in); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+	if NewSymbol("eth", "btc") != "ETH-BTC" {
+		t.Error("unexpected NewSymbol")
+	}
+}
+
+func TestParseSideAndOrderType(t *testing.T) {
+	if s, err := ParseSide("SELL"); err != nil || s != SideSell {
+		t.Errorf("ParseSide: %s %v", s, err)
+	}
+	if _, err := ParseSide("sel"); err == nil {
+		t.Error("expected error for side sel")
+	}
+	if ot, err := ParseOrderType("Market"); err != nil || ot != OrderTypeMarket {
+		t.Errorf("ParseOrderType: %s %v", ot, err)
+	}
+	if _, err := ParseOrderType("stop"); err == nil {
+		t.Error("expected error for type stop")
+	}
+}
+
+func TestSymbolUnmarshalNormalises(t *testing.T) {
+	var o Order
+	if err := json.Unmarshal([]byte(`{"market":"btc-mxn","side":"buy"}`), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Market != "BTC-MXN" || o.Side != SideBuy {
+		t.Errorf("unexpected order %+v", o)
+	}
+}
+
+func TestPlaceOrderValidates(t *testing.T) {
+	c, _ := NewClient("key", "c2VjcmV0", WithTransport(&failingTransport{}))
+	_, err := c.PlaceOrder(NewOrder{Market: "btc_mxn", Side: "sel", Type: OrderTypeLimit, Amount: MustDecimal("1")})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	rt := &failingTransport{}
+	c, _ = NewClient("key", "c2VjcmV0", WithTransport(rt))
+	c.PlaceOrder(NewOrder{Market: "BTC-MXN", Side: "sel", Type: OrderTypeMarket, Amount: MustDecimal("1")})
+	if rt.calls != 0 {
+		t.Error("invalid order was sent")
+	}
+}
+
+func TestPlaceOrderNormalises(t *testing.T) {
+	var body string
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Write([]byte(`{"success":true,"data":{"id":1}}`))
+	}))
+	if _, err := c.PlaceOrder(NewOrder{Market: "BTC_MXN", Side: " BUY", Type: "Limit", Amount: MustDecimal("1"), Price: MustDecimal("2")}); err != nil {
+		t.Fatal(err)
+	}
+	if body != `{"market":"btc-mxn","side":"buy","amount":"1","type":"limit","price":"2"}` {
+		t.Errorf("unexpected body %s", body)
+	}
+}
+
+func TestParseOrderStatus(t *testing.T) {
+	for in, want := range map[string]OrderStatus{
+		"filled": OrderStatusFilled, "Canceled": OrderStatusCancelled, "cancelled": OrderStatusCancelled,
+		"partially_filled": OrderStatusPartial, "OPEN": OrderStatusOpen,
+	} {
+		if s, err := ParseOrderStatus(in); err != nil || s != want {
+			t.Errorf("%q: got %q, %v", in, s, err)
+		}
+	}
+	if _, err := ParseOrderStatus("expired"); err == nil {
+		t.Error("expected error for unknown status")
+	}
+}
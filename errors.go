@@ -0,0 +1,136 @@
+package taurosapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by APIError through errors.Is
+var (
+	ErrInvalidToken      = errors.New("tauros: invalid token")
+	ErrBadSignature      = errors.New("tauros: bad signature")
+	ErrInsufficientFunds = errors.New("tauros: insufficient funds")
+	ErrWebhookLimit      = errors.New("tauros: webhook limit reached")
+	ErrMarketClosed      = errors.New("tauros: market closed")
+)
+
+// APIError - error returned by the Tauros API for a request
+type APIError struct {
+	StatusCode int    // http status code of the response
+	Method     string // http method of the request
+	Path       string // endpoint path without the /api/vN/ prefix
+	Version    int    // api version of the endpoint
+	Message    string // decoded "msg" (or "detail") sent by Tauros
+	Body       []byte // raw response body
+
+	RetryAfter time.Duration // delay asked by the Retry-After header, if any
+
+	debug string // nonce and secret fingerprint of a rejected signature, appended to Error()
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("Tauros API %s /api/v%d/%s (%d): %s%s", e.Method, e.Version, e.Path, e.StatusCode, msg, e.debug)
+}
+
+// Is - match the sentinel errors against the status code and message sent by Tauros
+func (e *APIError) Is(target error) bool {
+	msg := strings.ToLower(e.Message + " " + string(e.Body))
+	switch target {
+	case ErrBadSignature:
+		return strings.Contains(msg, "signature")
+	case ErrInvalidToken:
+		return strings.Contains(msg, "invalid token") ||
+			(e.StatusCode == http.StatusUnauthorized && !strings.Contains(msg, "signature"))
+	case ErrInsufficientFunds:
+		return strings.Contains(msg, "insufficient") || strings.Contains(msg, "not enough") ||
+			strings.Contains(msg, "fondos insuficientes")
+	case ErrWebhookLimit:
+		return strings.Contains(msg, "limit reached")
+	case ErrMarketClosed:
+		return strings.Contains(msg, "market is closed") || strings.Contains(msg, "market closed") ||
+			strings.Contains(msg, "closed market")
+	}
+	return false
+}
+
+// Temporary - true for server side (5xx) and throttling (429) errors that may succeed when retried
+func (e *APIError) Temporary() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// decodeAPIMessage turns the "msg" field of a response, which Tauros sends
+// either as a string or as a list of strings, into a single message
+func decodeAPIMessage(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var l []string
+	if err := json.Unmarshal(raw, &l); err == nil {
+		return strings.Join(l, "; ")
+	}
+	return string(raw)
+}
+
+// newAPIError builds the APIError for a failed response, looking for the
+// message in the "msg" and "detail" fields or in a bare list of strings
+func newAPIError(tauReq *TauReq, statusCode int, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Method:     tauReq.Method,
+		Path:       tauReq.Path,
+		Version:    tauReq.Version,
+		Body:       body,
+	}
+	var d struct {
+		Message json.RawMessage `json:"msg"`
+		Detail  string          `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &d); err == nil {
+		e.Message = decodeAPIMessage(d.Message)
+		if e.Message == "" {
+			e.Message = d.Detail
+		}
+	} else if json.Valid(body) {
+		e.Message = decodeAPIMessage(body)
+	}
+	return e
+}
+
+// WebhookLimitError - CreateWebhook was refused because the account already
+// has the maximum number of webhooks; errors.Is(err, ErrWebhookLimit) matches it
+type WebhookLimitError struct {
+	Count int   // webhooks registered when the limit was hit, -1 if they could not be listed
+	Max   int   // webhooks allowed per account
+	Err   error // error returned by Tauros
+}
+
+func (e *WebhookLimitError) Error() string {
+	count := "?"
+	if e.Count >= 0 {
+		count = strconv.Itoa(e.Count)
+	}
+	return fmt.Sprintf("webhook limit reached (%s/%d): %v", count, e.Max, e.Err)
+}
+
+// Is - match ErrWebhookLimit
+func (e *WebhookLimitError) Is(target error) bool {
+	return target == ErrWebhookLimit
+}
+
+// Unwrap - the error returned by Tauros
+func (e *WebhookLimitError) Unwrap() error {
+	return e.Err
+}
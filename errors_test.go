@@ -0,0 +1,99 @@
+package taurosapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIErrorSentinels(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		target error
+	}{
+		{http.StatusUnauthorized, `{"detail":"Invalid token."}`, ErrInvalidToken},
+		{http.StatusBadRequest, `{"success":false,"msg":"Invalid signature"}`, ErrBadSignature},
+		{http.StatusBadRequest, `{"success":false,"msg":["Insufficient funds"]}`, ErrInsufficientFunds},
+		{http.StatusBadRequest, `["Limit reached"]`, ErrWebhookLimit},
+		{http.StatusBadRequest, `{"success":false,"msg":"Market is closed"}`, ErrMarketClosed},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		}))
+		c, _ := NewClient("key", "c2VjcmV0", WithBaseURL(srv.URL))
+		_, err := c.GetBalances()
+		srv.Close()
+		if !errors.Is(err, tt.target) {
+			t.Errorf("%s: expected %v, got %v", tt.body, tt.target, err)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected *APIError, got %T", err)
+		}
+		if apiErr.StatusCode != tt.status || apiErr.Method != "GET" || apiErr.Version != 1 {
+			t.Errorf("unexpected api error fields %+v", apiErr)
+		}
+	}
+}
+
+func TestAPIErrorTemporary(t *testing.T) {
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>bad gateway</html>"))
+	}))
+	_, err := c.GetCoins()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.Temporary() {
+		t.Errorf("expected temporary APIError, got %v", err)
+	}
+}
+
+func TestWebhookLimitError(t *testing.T) {
+	fake := &fakeWebhooks{}
+	for i := 0; i < MaxWebhooks; i++ {
+		fake.nextID++
+		fake.hooks = append(fake.hooks, Webhook{ID: fake.nextID})
+	}
+	c, _ := newTestClient(t, fake)
+	_, err := c.CreateWebhook(Webhook{Name: "one too many"})
+	var limitErr *WebhookLimitError
+	if !errors.As(err, &limitErr) || limitErr.Count != MaxWebhooks || limitErr.Max != MaxWebhooks {
+		t.Fatalf("expected WebhookLimitError with count %d, got %v", MaxWebhooks, err)
+	}
+	var apiErr *APIError
+	if !errors.Is(err, ErrWebhookLimit) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("limit error does not match ErrWebhookLimit and the APIError: %v", err)
+	}
+
+	fake.hooks = fake.hooks[:1]
+	created, err := c.CreateWebhook(Webhook{Name: "hook", NotifyTrade: true})
+	if err != nil || created.ID == 0 || created.Name != "hook" || !created.NotifyTrade {
+		t.Errorf("unexpected created webhook %+v, %v", created, err)
+	}
+}
+
+func TestCreateWebhookLimitWithOKStatus(t *testing.T) {
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"results":[{"id":1},{"id":2}]}`))
+			return
+		}
+		w.Write([]byte(`["Limit reached"]`))
+	}))
+	_, err := c.CreateWebhook(Webhook{Name: "hook"})
+	var limitErr *WebhookLimitError
+	if !errors.As(err, &limitErr) || limitErr.Count != 2 {
+		t.Errorf("expected WebhookLimitError with count 2, got %v", err)
+	}
+}
+
+func TestCreateWebhookTransportError(t *testing.T) {
+	c, _ := NewClient("key", "c2VjcmV0", WithBaseURL("http://127.0.0.1:1"), WithRetryPolicy(NoRetry))
+	if w, err := c.CreateWebhook(Webhook{Name: "hook"}); err == nil || w.ID != 0 {
+		t.Errorf("expected transport error, got %+v, %v", w, err)
+	}
+}
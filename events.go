@@ -0,0 +1,149 @@
+package taurosapi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Event - notification envelope shared by websocket and webhook delivery;
+// Object holds the raw payload, decoded according to Type by Payload
+type Event struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Type        string          `json:"type"`
+	Date        Timestamp       `json:"date"`
+	Object      json.RawMessage `json:"object"`
+}
+
+// TauWsMessage - Tauros Websocket message, same envelope as webhooks
+type TauWsMessage = Event
+
+// TauWebHookMessage - Tauros POST message received via webhooks, same envelope as websockets
+type TauWebHookMessage = Event
+
+// EventPayload - typed payload of an Event, one of *OrderPlacedEvent,
+// *OrderFilledEvent, *TradeEvent, *DepositEvent, *WithdrawalEvent,
+// *InnerTransferEvent or *UnknownEvent
+type EventPayload interface {
+	EventType() string
+}
+
+// Payload - decode Object according to Type; unknown types give an *UnknownEvent keeping the raw JSON
+func (e *Event) Payload() (EventPayload, error) {
+	var p EventPayload
+	switch e.Type {
+	case EventOrderPlaced:
+		p = &OrderPlacedEvent{}
+	case EventOrderFilled:
+		p = &OrderFilledEvent{}
+	case EventTrade:
+		p = &TradeEvent{}
+	case EventDeposit:
+		p = &DepositEvent{}
+	case EventWithdrawal:
+		p = &WithdrawalEvent{}
+	case EventInnerTransfer:
+		p = &InnerTransferEvent{}
+	default:
+		return &UnknownEvent{Type: e.Type, Raw: e.Object}, nil
+	}
+	if len(e.Object) == 0 {
+		return p, nil
+	}
+	if err := json.Unmarshal(e.Object, p); err != nil {
+		return nil, fmt.Errorf("Event %s payload-> %w", e.Type, err)
+	}
+	return p, nil
+}
+
+// OrderEventData - order fields common to order placed and order filled events
+type OrderEventData struct {
+	ID            int64     `json:"id"`
+	Market        Symbol    `json:"market"`
+	Side          Side      `json:"side"`
+	LeftCoin      string    `json:"left_coin"`
+	RightCoin     string    `json:"right_coin"`
+	InitialAmount Decimal   `json:"initial_amount"`
+	Amount        Decimal   `json:"amount"`
+	Filled        Decimal   `json:"filled"`
+	InitialValue  Decimal   `json:"initial_value"`
+	Value         Decimal   `json:"value"`
+	Price         Decimal   `json:"price"`
+	FeeDecimal    Decimal   `json:"fee_decimal"`
+	FeePercent    Decimal   `json:"fee_percent"`
+	IsOpen        bool      `json:"is_open"`
+	CreatedAt     Timestamp `json:"created_at"`
+	ClosedAt      Timestamp `json:"closed_at"`
+}
+
+// OrderPlacedEvent - an order of the user was placed
+type OrderPlacedEvent struct {
+	OrderEventData
+}
+
+// EventType - EventOrderPlaced
+func (*OrderPlacedEvent) EventType() string { return EventOrderPlaced }
+
+// OrderFilledEvent - an order of the user was (partially) filled
+type OrderFilledEvent struct {
+	OrderEventData
+	FeeAmountPaid       Decimal `json:"fee_amount_paid"`
+	AmountPaid          Decimal `json:"amount_paid"`
+	AmountReceived      Decimal `json:"amount_received"`
+	TradeAmountPaid     Decimal `json:"trade_amount_paid"` //the actual amounts of this fill
+	TradeAmountReceived Decimal `json:"trade_amount_received"`
+}
+
+// EventType - EventOrderFilled
+func (*OrderFilledEvent) EventType() string { return EventOrderFilled }
+
+// TradeEvent - a trade involving an order of the user
+type TradeEvent struct {
+	ID             int64     `json:"id"`
+	Market         Symbol    `json:"market"`
+	Side           Side      `json:"side"`
+	Amount         Decimal   `json:"amount"`
+	Price          Decimal   `json:"price"`
+	Value          Decimal   `json:"value"`
+	AmountPaid     Decimal   `json:"amount_paid"`
+	AmountReceived Decimal   `json:"amount_received"`
+	FeeAmountPaid  Decimal   `json:"fee_amount_paid"`
+	FeeDecimal     Decimal   `json:"fee_decimal"`
+	CreatedAt      Timestamp `json:"created_at"`
+}
+
+// EventType - EventTrade
+func (*TradeEvent) EventType() string { return EventTrade }
+
+// DepositEvent - funds were deposited into a wallet of the user
+type DepositEvent struct {
+	Deposit
+}
+
+// EventType - EventDeposit
+func (*DepositEvent) EventType() string { return EventDeposit }
+
+// WithdrawalEvent - funds were withdrawn from a wallet of the user
+type WithdrawalEvent struct {
+	Withdrawal
+}
+
+// EventType - EventWithdrawal
+func (*WithdrawalEvent) EventType() string { return EventWithdrawal }
+
+// InnerTransferEvent - funds moved between Tauros accounts
+type InnerTransferEvent struct {
+	InnerTransfer
+}
+
+// EventType - EventInnerTransfer
+func (*InnerTransferEvent) EventType() string { return EventInnerTransfer }
+
+// UnknownEvent - event of a type this package does not know yet
+type UnknownEvent struct {
+	Type string
+	Raw  json.RawMessage
+}
+
+// EventType - the type sent by Tauros
+func (e *UnknownEvent) EventType() string { return e.Type }
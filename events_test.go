@@ -0,0 +1,56 @@
+package taurosapi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEventPayload(t *testing.T) {
+	tests := []struct {
+		msg   string
+		check func(EventPayload) bool
+	}{
+		{`{"type":"order_placed","object":{"id":1,"market":"btc-mxn","side":"buy","price":"100"}}`, func(p EventPayload) bool {
+			o, ok := p.(*OrderPlacedEvent)
+			return ok && o.ID == 1 && o.Market == "BTC-MXN" && o.Side == SideBuy && o.Price.String() == "100"
+		}},
+		{`{"type":"order_filled","object":{"id":2,"trade_amount_paid":"1.5"}}`, func(p EventPayload) bool {
+			o, ok := p.(*OrderFilledEvent)
+			return ok && o.ID == 2 && o.TradeAmountPaid.String() == "1.5"
+		}},
+		{`{"type":"trade","object":{"id":3,"amount":"0.1"}}`, func(p EventPayload) bool {
+			o, ok := p.(*TradeEvent)
+			return ok && o.ID == 3 && o.Amount.String() == "0.1"
+		}},
+		{`{"type":"deposit","object":{"coin":"BTC","txId":"abc","confirmed":true,"confirmed_at":"2020-01-02T03:04:05Z"}}`, func(p EventPayload) bool {
+			o, ok := p.(*DepositEvent)
+			return ok && o.TxID == "abc" && o.Confirmed && o.ConfirmedAt.Year() == 2020
+		}},
+		{`{"type":"withdrawal","object":{"coin":"ETH","fee_amount":"0.01"}}`, func(p EventPayload) bool {
+			o, ok := p.(*WithdrawalEvent)
+			return ok && o.Coin == "ETH" && o.FeeAmount.String() == "0.01"
+		}},
+		{`{"type":"inner_transfer","object":{"receiver":"a@b.c"}}`, func(p EventPayload) bool {
+			o, ok := p.(*InnerTransferEvent)
+			return ok && o.Receiver == "a@b.c"
+		}},
+		{`{"type":"margin_call","object":{"x":1}}`, func(p EventPayload) bool {
+			o, ok := p.(*UnknownEvent)
+			return ok && o.EventType() == "margin_call" && string(o.Raw) == `{"x":1}`
+		}},
+	}
+	for _, tt := range tests {
+		var e Event
+		if err := json.Unmarshal([]byte(tt.msg), &e); err != nil {
+			t.Fatal(err)
+		}
+		p, err := e.Payload()
+		if err != nil {
+			t.Errorf("%s: %v", tt.msg, err)
+			continue
+		}
+		if !tt.check(p) || p.EventType() != e.Type {
+			t.Errorf("%s: unexpected payload %+v", tt.msg, p)
+		}
+	}
+}
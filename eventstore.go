@@ -0,0 +1,163 @@
+package taurosapi
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Key - identity of an event used for deduplication: its type, object ID and
+// tx id followed by a hash of the whole object, so that updates of one object
+// (another partial fill, a deposit getting confirmed) get their own key. The
+// envelope date is left out as a redelivery may be stamped again.
+func (e *Event) Key() string {
+	var obj struct {
+		ID   int64  `json:"id"`
+		TxID string `json:"txId"`
+	}
+	json.Unmarshal(e.Object, &obj) //a missing or odd object leaves the zero values
+	sum := sha256.Sum256(canonicalJSON(e.Object))
+	return strings.Join([]string{e.Type, strconv.FormatInt(obj.ID, 10), obj.TxID, hex.EncodeToString(sum[:16])}, "|")
+}
+
+// canonicalJSON re-encodes data with sorted keys and no spacing, so the same
+// object sent with its fields in another order hashes the same
+func canonicalJSON(data []byte) []byte {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return data
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// EventStore - remembers which events were already processed
+type EventStore interface {
+	// Seen reports whether key was marked as processed
+	Seen(key string) (bool, error)
+	// MarkProcessed records key as processed
+	MarkProcessed(key string) error
+}
+
+// MemoryEventStore - EventStore keeping the most recent keys in memory, evicting the least recently used
+type MemoryEventStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is the most recently used key
+	keys     map[string]*list.Element
+}
+
+// NewMemoryEventStore - in-memory store remembering up to capacity keys
+func NewMemoryEventStore(capacity int) *MemoryEventStore {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &MemoryEventStore{
+		capacity: capacity,
+		order:    list.New(),
+		keys:     make(map[string]*list.Element),
+	}
+}
+
+// Seen - whether key is remembered
+func (s *MemoryEventStore) Seen(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.keys[key]; ok {
+		s.order.MoveToFront(e)
+		return true, nil
+	}
+	return false, nil
+}
+
+// MarkProcessed - remember key, evicting the least recently used key when full
+func (s *MemoryEventStore) MarkProcessed(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.keys[key]; ok {
+		s.order.MoveToFront(e)
+		return nil
+	}
+	s.keys[key] = s.order.PushFront(key)
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.keys, oldest.Value.(string))
+	}
+	return nil
+}
+
+// FileEventStore - EventStore persisting the keys in an append-only file, one per line,
+// so processed events survive restarts
+type FileEventStore struct {
+	mu   sync.Mutex
+	f    *os.File
+	keys map[string]bool
+}
+
+// OpenFileEventStore - open (or create) the store at path and load the keys it already holds
+func OpenFileEventStore(path string) (*FileEventStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("OpenFileEventStore-> %w", err)
+	}
+	s := &FileEventStore{f: f, keys: make(map[string]bool)}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			s.keys[line] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("OpenFileEventStore-> %w", err)
+	}
+	return s, nil
+}
+
+// Seen - whether key was recorded
+func (s *FileEventStore) Seen(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys[key], nil
+}
+
+// MarkProcessed - append key to the file and sync it to disk
+func (s *FileEventStore) MarkProcessed(key string) error {
+	if strings.ContainsAny(key, "\r\n") {
+		return fmt.Errorf("FileEventStore-> invalid key %q", key)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys[key] {
+		return nil
+	}
+	if _, err := s.f.WriteString(key + "\n"); err != nil {
+		return fmt.Errorf("FileEventStore-> %w", err)
+	}
+	if err := s.f.Sync(); err != nil {
+		return fmt.Errorf("FileEventStore-> %w", err)
+	}
+	s.keys[key] = true
+	return nil
+}
+
+// Close - close the underlying file
+func (s *FileEventStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
@@ -0,0 +1,99 @@
+package taurosapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEventKey(t *testing.T) {
+	key := func(in string) string {
+		var e Event
+		if err := json.Unmarshal([]byte(in), &e); err != nil {
+			t.Fatal(err)
+		}
+		return e.Key()
+	}
+	deposit := key(`{"type":"deposit","date":"2020-01-02T03:04:05Z","object":{"id":7,"txId":"abc","confirmed":false}}`)
+	if !strings.HasPrefix(deposit, "deposit|7|abc|") {
+		t.Errorf("unexpected key %s", deposit)
+	}
+	same := []string{
+		`{"type":"deposit","date":"2020-01-03T00:00:00Z","object":{"id":7,"txId":"abc","confirmed":false}}`, //redelivery stamped again
+		`{"type":"deposit","object":{ "confirmed":false, "txId":"abc", "id":7 }}`,                           //fields in another order
+	}
+	for _, in := range same {
+		if k := key(in); k != deposit {
+			t.Errorf("%s: got key %s, want %s", in, k, deposit)
+		}
+	}
+	distinct := map[string][2]string{
+		"partial fills": {
+			`{"type":"order_filled","object":{"id":42,"filled":"0.5"}}`,
+			`{"type":"order_filled","object":{"id":42,"filled":"1.0"}}`,
+		},
+		"pending then confirmed": {
+			`{"type":"deposit","object":{"id":7,"txId":"abc","confirmed":false}}`,
+			`{"type":"deposit","object":{"id":7,"txId":"abc","confirmed":true}}`,
+		},
+		"types": {
+			`{"type":"deposit","object":{"id":7}}`,
+			`{"type":"withdrawal","object":{"id":7}}`,
+		},
+		"objects without ids": {
+			`{"type":"trade","object":{"amount":"1"}}`,
+			`{"type":"trade","object":{"amount":"2"}}`,
+		},
+	}
+	for name, pair := range distinct {
+		if key(pair[0]) == key(pair[1]) {
+			t.Errorf("%s share a key", name)
+		}
+	}
+}
+
+func TestMemoryEventStoreEvicts(t *testing.T) {
+	s := NewMemoryEventStore(2)
+	s.MarkProcessed("a")
+	s.MarkProcessed("b")
+	s.Seen("a") //a becomes the most recently used
+	s.MarkProcessed("c")
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true, "d": false} {
+		if seen, _ := s.Seen(key); seen != want {
+			t.Errorf("Seen(%s) = %v, want %v", key, seen, want)
+		}
+	}
+}
+
+func TestFileEventStorePersists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eventstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.log")
+	s, err := OpenFileEventStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.MarkProcessed("deposit|7|abc|")
+	s.MarkProcessed("deposit|7|abc|")
+	s.Close()
+	if data, _ := ioutil.ReadFile(path); string(data) != "deposit|7|abc|\n" {
+		t.Errorf("unexpected file content %q", data)
+	}
+	s, err = OpenFileEventStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if seen, _ := s.Seen("deposit|7|abc|"); !seen {
+		t.Error("key lost after reopening")
+	}
+	if err := s.MarkProcessed("bad\nkey"); err == nil {
+		t.Error("expected error for key with newline")
+	}
+}
@@ -0,0 +1,63 @@
+package taurosapi
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// NonceSource - provides the Taur-Nonce of signed requests, values must be strictly increasing
+type NonceSource interface {
+	Nonce() int64
+}
+
+// MonotonicNonce - default NonceSource, based on the clock in milliseconds but
+// never returning the same or a lower value twice, even when called from
+// several goroutines in the same millisecond or after the clock goes back
+type MonotonicNonce struct {
+	last   int64 // last nonce returned
+	offset int64 // milliseconds added to the local clock
+}
+
+// defaultNonceSource is shared by every TauAPI value not built with NewClient
+var defaultNonceSource = NewMonotonicNonce()
+
+// NewMonotonicNonce - create a MonotonicNonce without clock offset
+func NewMonotonicNonce() *MonotonicNonce {
+	return &MonotonicNonce{}
+}
+
+// SetClockOffset - shift the nonces by the measured difference between the server clock and the local one
+func (n *MonotonicNonce) SetClockOffset(offset time.Duration) {
+	atomic.StoreInt64(&n.offset, int64(offset/time.Millisecond))
+}
+
+// Nonce - next nonce
+func (n *MonotonicNonce) Nonce() int64 {
+	for {
+		next := time.Now().UnixNano()/int64(time.Millisecond) + atomic.LoadInt64(&n.offset)
+		last := atomic.LoadInt64(&n.last)
+		if next <= last {
+			next = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&n.last, last, next) {
+			return next
+		}
+	}
+}
+
+// WithNonceSource - generate the nonces of signed requests with ns
+func WithNonceSource(ns NonceSource) Option {
+	return func(cfg *clientConfig) { cfg.nonceSource = ns }
+}
+
+// WithClockOffset - apply the measured server clock offset (server time minus local time) to the default nonces
+func WithClockOffset(offset time.Duration) Option {
+	return func(cfg *clientConfig) { cfg.clockOffset = offset }
+}
+
+func (t *TauAPI) nonce() int64 {
+	if t.nonceSource != nil {
+		return t.nonceSource.Nonce()
+	}
+	return defaultNonceSource.Nonce()
+}
@@ -0,0 +1,44 @@
+package taurosapi
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMonotonicNonceConcurrent(t *testing.T) {
+	n := NewMonotonicNonce()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[int64]bool)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				v := n.Nonce()
+				mu.Lock()
+				if seen[v] {
+					t.Errorf("duplicate nonce %d", v)
+				}
+				seen[v] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMonotonicNonceClockOffset(t *testing.T) {
+	n := NewMonotonicNonce()
+	first := n.Nonce()
+	n.SetClockOffset(-time.Hour)
+	if second := n.Nonce(); second <= first {
+		t.Errorf("nonce went backwards: %d after %d", second, first)
+	}
+	n = NewMonotonicNonce()
+	n.SetClockOffset(time.Hour)
+	if v := n.Nonce(); v < time.Now().Add(59*time.Minute).UnixNano()/int64(time.Millisecond) {
+		t.Errorf("clock offset not applied: %d", v)
+	}
+}
@@ -0,0 +1,160 @@
+package taurosapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Environment - base URL of a Tauros API deployment
+type Environment string
+
+const (
+	// Production - live Tauros API
+	Production Environment = "https://api.tauros.io"
+	// Staging - Tauros sandbox API
+	Staging Environment = "https://api.staging.tauros.io"
+)
+
+// DefaultTimeout - timeout of the http client used when none is configured
+const DefaultTimeout = 3 * time.Second
+
+// DefaultUserAgent - User-Agent header sent when none is configured
+const DefaultUserAgent = "gotauros"
+
+// defaultHTTPClient is shared by every TauAPI value that was not built with
+// NewClient (e.g. unmarshalled from tokens.json) so they reuse one pool
+var defaultHTTPClient = &http.Client{Timeout: DefaultTimeout}
+
+// Option - configures a TauAPI built with NewClient
+type Option func(*clientConfig)
+
+type clientConfig struct {
+	httpClient *http.Client
+	transport  http.RoundTripper
+	timeout    time.Duration
+	userAgent  string
+	url        string
+
+	debugLogger Logger
+	retryPolicy *RetryPolicy
+
+	publicLimiter  *RateLimiter
+	privateLimiter *RateLimiter
+	limitersSet    bool
+
+	nonceSource NonceSource
+	clockOffset time.Duration
+
+	policy *Policy
+}
+
+// WithHTTPClient - use the given http client instead of a private one, e.g. to share a connection pool
+func WithHTTPClient(c *http.Client) Option {
+	return func(cfg *clientConfig) { cfg.httpClient = c }
+}
+
+// WithTransport - send requests through the given RoundTripper, e.g. a proxy or a test double
+func WithTransport(rt http.RoundTripper) Option {
+	return func(cfg *clientConfig) { cfg.transport = rt }
+}
+
+// WithTimeout - overall timeout of each http request (default 3 seconds)
+func WithTimeout(d time.Duration) Option {
+	return func(cfg *clientConfig) { cfg.timeout = d }
+}
+
+// WithUserAgent - User-Agent header sent with every request
+func WithUserAgent(ua string) Option {
+	return func(cfg *clientConfig) { cfg.userAgent = ua }
+}
+
+// WithEnvironment - point the client at one of the Tauros deployments (default Production)
+func WithEnvironment(env Environment) Option {
+	return func(cfg *clientConfig) { cfg.url = string(env) }
+}
+
+// WithBaseURL - point the client at an arbitrary base URL, e.g. a local fake server
+func WithBaseURL(url string) Option {
+	return func(cfg *clientConfig) { cfg.url = url }
+}
+
+// NewClient - build a TauAPI for the given credentials; the secret must be the base64 string shown by Tauros
+func NewClient(apiKey, apiSecret string, opts ...Option) (*TauAPI, error) {
+	signer, err := NewSigner(apiKey, apiSecret)
+	if err != nil {
+		return nil, fmt.Errorf("NewClient-> %w", err)
+	}
+	cfg := clientConfig{
+		url:       string(Production),
+		userAgent: DefaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.nonceSource == nil {
+		cfg.nonceSource = NewMonotonicNonce()
+	}
+	if n, ok := cfg.nonceSource.(*MonotonicNonce); ok && cfg.clockOffset != 0 {
+		n.SetClockOffset(cfg.clockOffset)
+	}
+	if !cfg.limitersSet {
+		cfg.publicLimiter = NewRateLimiter(DefaultPublicRate, DefaultPublicBurst)
+		cfg.privateLimiter = NewRateLimiter(DefaultPrivateRate, DefaultPrivateBurst)
+	}
+	var c http.Client
+	if cfg.httpClient != nil {
+		c = *cfg.httpClient
+	} else {
+		c.Timeout = DefaultTimeout
+	}
+	if cfg.transport != nil {
+		c.Transport = cfg.transport
+	}
+	if cfg.timeout != 0 {
+		c.Timeout = cfg.timeout
+	}
+	return &TauAPI{
+		APIKey:     apiKey,
+		APISecret:  apiSecret,
+		URL:        cfg.url,
+		httpClient: &c,
+		userAgent:  cfg.userAgent,
+
+		debugLogger: cfg.debugLogger,
+		retryPolicy: cfg.retryPolicy,
+
+		publicLimiter:  cfg.publicLimiter,
+		privateLimiter: cfg.privateLimiter,
+		limitersSet:    true,
+		nonceSource:    cfg.nonceSource,
+		signer:         signer,
+		signerKey:      apiKey,
+		signerSecret:   apiSecret,
+
+		policy: cfg.policy,
+	}, nil
+}
+
+func (t *TauAPI) client() *http.Client {
+	if t.httpClient != nil {
+		return t.httpClient
+	}
+	return defaultHTTPClient
+}
+
+// requestSigner returns the Signer of the client, rebuilt from the APIKey and
+// APISecret fields whenever they changed since it was built
+func (t *TauAPI) requestSigner() (*Signer, error) {
+	t.signerMu.Lock()
+	defer t.signerMu.Unlock()
+	if t.signer != nil && t.signerKey == t.APIKey && t.signerSecret == t.APISecret {
+		return t.signer, nil
+	}
+	signer, err := NewSigner(t.APIKey, t.APISecret)
+	if err != nil {
+		return nil, err
+	}
+	t.signer, t.signerKey, t.signerSecret = signer, t.APIKey, t.APISecret
+	return signer, nil
+}
@@ -0,0 +1,83 @@
+package taurosapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestClient serves handler on a test server closed with the test and
+// returns a client of it; requests are not retried unless opts set a RetryPolicy
+func newTestClient(t *testing.T, handler http.Handler, opts ...Option) (*TauAPI, *httptest.Server) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c, err := NewClient("key", "c2VjcmV0", append([]Option{WithBaseURL(srv.URL), WithRetryPolicy(NoRetry)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, srv
+}
+
+func TestNewClientInvalidSecret(t *testing.T) {
+	if _, err := NewClient("key", "not base64!"); err == nil {
+		t.Error("expected error for invalid base64 secret")
+	}
+}
+
+func TestNewClientOptions(t *testing.T) {
+	var userAgent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"success":true,"payload":{"cryto":[{"coin":"BTC"}],"fiat":[{"coin":"MXN"}]}}`))
+	}))
+	defer srv.Close()
+	c, err := NewClient("key", "c2VjcmV0", WithBaseURL(srv.URL), WithUserAgent("bot/1.0"), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.client().Timeout != time.Second {
+		t.Errorf("timeout not applied: %s", c.client().Timeout)
+	}
+	coins, err := c.GetCoins()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coins) != 2 {
+		t.Errorf("expected 2 coins, got %d", len(coins))
+	}
+	if userAgent != "bot/1.0" {
+		t.Errorf("unexpected user agent %q", userAgent)
+	}
+}
+
+func TestNewClientEnvironment(t *testing.T) {
+	c, err := NewClient("key", "c2VjcmV0", WithEnvironment(Staging))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.URL != string(Staging) {
+		t.Errorf("unexpected url %s", c.URL)
+	}
+}
+
+func TestClientCredentialsChange(t *testing.T) {
+	c, _ := NewClient("key", "c2VjcmV0")
+	first, err := c.requestSigner()
+	if err != nil || first.APIKey != "key" {
+		t.Fatalf("unexpected signer %v, %v", first, err)
+	}
+	if again, _ := c.requestSigner(); again != first {
+		t.Error("signer rebuilt without a credentials change")
+	}
+	c.APIKey, c.APISecret = "other", "b3RoZXI="
+	rotated, err := c.requestSigner()
+	if err != nil || rotated.APIKey != "other" || rotated.Fingerprint() == first.Fingerprint() {
+		t.Errorf("new credentials ignored: %v, %v", rotated, err)
+	}
+	c.APISecret = "not base64!"
+	if _, err := c.requestSigner(); err == nil {
+		t.Error("expected error for an invalid secret")
+	}
+}
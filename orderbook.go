@@ -0,0 +1,289 @@
+package taurosapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Public market data websocket endpoints of the Tauros deployments
+const (
+	WsMarketProduction = "wss://ws.tauros.io/ws/v1/market/"
+	WsMarketStaging    = "wss://ws.staging.tauros.io/ws/v1/market/"
+)
+
+// PriceLevel - aggregated amount offered at one price
+type PriceLevel struct {
+	Price  Decimal `json:"price"`
+	Amount Decimal `json:"amount"`
+}
+
+// bookMessage is a snapshot or incremental update of a market order book;
+// levels of an update with zero amount are removed from the book
+type bookMessage struct {
+	Type     string       `json:"type"` // "snapshot" or "update"
+	Market   Symbol       `json:"market"`
+	Sequence int64        `json:"sequence"`
+	Bids     []PriceLevel `json:"bids"`
+	Asks     []PriceLevel `json:"asks"`
+}
+
+// OrderBook - locally maintained order book of one market, safe for concurrent use
+type OrderBook struct {
+	Market Symbol
+
+	mu       sync.RWMutex
+	bids     map[string]PriceLevel // keyed by normalised price
+	asks     map[string]PriceLevel
+	sequence int64
+	synced   bool
+	updated  time.Time
+	changes  chan struct{}
+}
+
+// NewOrderBook - empty order book of market, filled by a MarketStream
+func NewOrderBook(market Symbol) *OrderBook {
+	return &OrderBook{
+		Market:  market,
+		bids:    make(map[string]PriceLevel),
+		asks:    make(map[string]PriceLevel),
+		changes: make(chan struct{}, 1),
+	}
+}
+
+// Changes - receives a value after the book changes; notifications are
+// coalesced, so a slow reader only sees that something changed since it last looked
+func (b *OrderBook) Changes() <-chan struct{} {
+	return b.changes
+}
+
+// BestBid - highest bid, ok is false when there are no bids
+func (b *OrderBook) BestBid() (level PriceLevel, ok bool) {
+	bids, _ := b.Depth(1)
+	if len(bids) == 0 {
+		return PriceLevel{}, false
+	}
+	return bids[0], true
+}
+
+// BestAsk - lowest ask, ok is false when there are no asks
+func (b *OrderBook) BestAsk() (level PriceLevel, ok bool) {
+	_, asks := b.Depth(1)
+	if len(asks) == 0 {
+		return PriceLevel{}, false
+	}
+	return asks[0], true
+}
+
+// Depth - best n levels of each side, bids descending and asks ascending; n <= 0 returns every level
+func (b *OrderBook) Depth(n int) (bids, asks []PriceLevel) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return sortedLevels(b.bids, true, n), sortedLevels(b.asks, false, n)
+}
+
+// Synced - whether the book reflects a snapshot and every update since then
+func (b *OrderBook) Synced() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.synced
+}
+
+// Sequence - sequence number of the last snapshot or update applied
+func (b *OrderBook) Sequence() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.sequence
+}
+
+// UpdatedAt - local time of the last change
+func (b *OrderBook) UpdatedAt() time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.updated
+}
+
+func sortedLevels(levels map[string]PriceLevel, descending bool, n int) []PriceLevel {
+	out := make([]PriceLevel, 0, len(levels))
+	for _, l := range levels {
+		out = append(out, l)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if descending {
+			return out[i].Price.GreaterThan(out[j].Price)
+		}
+		return out[i].Price.LessThan(out[j].Price)
+	})
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// priceKey normalises a price so 1.50 and 1.5 are the same level
+func priceKey(p Decimal) string {
+	s := p.String()
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// errSequenceGap is returned by apply when an update does not follow the last one applied
+type errSequenceGap struct {
+	want, got int64
+}
+
+func (e *errSequenceGap) Error() string {
+	return fmt.Sprintf("sequence gap: expected %d, got %d", e.want, e.got)
+}
+
+// apply a snapshot or update; updates received before the first snapshot or
+// older than the book are ignored, a gap unsyncs the book until the next snapshot
+func (b *OrderBook) apply(m *bookMessage) error {
+	b.mu.Lock()
+	switch m.Type {
+	case "snapshot":
+		b.bids = make(map[string]PriceLevel, len(m.Bids))
+		b.asks = make(map[string]PriceLevel, len(m.Asks))
+		setLevels(b.bids, m.Bids)
+		setLevels(b.asks, m.Asks)
+		b.sequence = m.Sequence
+		b.synced = true
+	case "update":
+		if !b.synced || m.Sequence <= b.sequence {
+			b.mu.Unlock()
+			return nil
+		}
+		if m.Sequence != b.sequence+1 {
+			b.synced = false
+			want := b.sequence + 1
+			b.mu.Unlock()
+			return &errSequenceGap{want: want, got: m.Sequence}
+		}
+		setLevels(b.bids, m.Bids)
+		setLevels(b.asks, m.Asks)
+		b.sequence = m.Sequence
+	default:
+		b.mu.Unlock()
+		return nil
+	}
+	b.updated = time.Now()
+	b.mu.Unlock()
+	select {
+	case b.changes <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func setLevels(book map[string]PriceLevel, levels []PriceLevel) {
+	for _, l := range levels {
+		k := priceKey(l.Price)
+		if l.Amount.IsZero() {
+			delete(book, k)
+		} else {
+			book[k] = l
+		}
+	}
+}
+
+// MarketStream - public websocket subscription keeping an OrderBook in sync
+//
+// The stream subscribes to the market, builds the book from the snapshot sent
+// by the server and applies the incremental updates. On a sequence gap it asks
+// for a new snapshot; on disconnection it reconnects and resubscribes.
+type MarketStream struct {
+	URL          string          // websocket endpoint, default WsMarketProduction
+	Transport    *http.Transport // dialer, proxy and TLS settings of the connection, nil for those of http.DefaultTransport
+	PingInterval time.Duration   // default (or when not positive) 15 seconds
+	StaleTimeout time.Duration   // reconnect when nothing is received for this long, default (or when not positive) 45 seconds
+	Backoff      RetryPolicy     // reconnection delays, MaxAttempts is ignored; delays not positive default to 1 and 30 seconds
+	Logger       Logger          // connection errors and resyncs, nil to discard
+
+	book *OrderBook
+
+	mu   sync.Mutex
+	conn *wsConn
+}
+
+// NewMarketStream - stream of the order book of market, e.g. "btc-mxn"
+func NewMarketStream(market Symbol) (*MarketStream, error) {
+	sym, err := ParseSymbol(string(market))
+	if err != nil {
+		return nil, fmt.Errorf("NewMarketStream-> %w", err)
+	}
+	return &MarketStream{
+		URL:          WsMarketProduction,
+		PingInterval: wsDefaultPingInterval,
+		StaleTimeout: wsDefaultStaleTimeout,
+		Backoff:      RetryPolicy{BaseDelay: wsDefaultBaseDelay, MaxDelay: wsDefaultMaxDelay},
+		book:         NewOrderBook(sym),
+	}, nil
+}
+
+// Book - the order book maintained by the stream
+func (s *MarketStream) Book() *OrderBook {
+	return s.book
+}
+
+// Run - keep the book in sync until ctx is done, then return ctx.Err()
+func (s *MarketStream) Run(ctx context.Context) error {
+	session := &wsSession{
+		url:       s.URL,
+		transport: s.Transport,
+		onConnect: func(ctx context.Context, conn *wsConn) error {
+			s.mu.Lock()
+			s.conn = conn
+			s.mu.Unlock()
+			s.book.mu.Lock()
+			s.book.synced = false //wait for the snapshot of this connection
+			s.book.mu.Unlock()
+			return s.send(conn, "subscribe")
+		},
+		onMessage:    s.handleMessage,
+		pingInterval: s.PingInterval,
+		staleTimeout: s.StaleTimeout,
+		backoff:      s.Backoff,
+		logger:       s.Logger,
+	}
+	return session.run(ctx)
+}
+
+func (s *MarketStream) send(conn *wsConn, action string) error {
+	msg, _ := json.Marshal(struct {
+		Action  string `json:"action"`
+		Channel string `json:"channel"`
+		Market  string `json:"market"`
+	}{action, "orderbook", s.book.Market.param()})
+	return conn.WriteMessage(wsOpText, msg)
+}
+
+func (s *MarketStream) handleMessage(data []byte) error {
+	var m bookMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("decode order book message: %w", err)
+	}
+	if m.Market != "" && m.Market != s.book.Market {
+		return nil
+	}
+	err := s.book.apply(&m)
+	if _, gap := err.(*errSequenceGap); gap {
+		s.mu.Lock()
+		conn := s.conn
+		s.mu.Unlock()
+		if conn == nil {
+			return err
+		}
+		if sendErr := s.send(conn, "snapshot"); sendErr != nil {
+			return fmt.Errorf("%v, resync failed: %w", err, sendErr)
+		}
+		return fmt.Errorf("%v, resyncing", err)
+	}
+	return err
+}
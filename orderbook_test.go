@@ -0,0 +1,108 @@
+package taurosapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOrderBookApply(t *testing.T) {
+	b := NewOrderBook("BTC-MXN")
+	if err := b.apply(&bookMessage{Type: "update", Sequence: 1, Bids: []PriceLevel{{MustDecimal("1"), MustDecimal("1")}}}); err != nil || b.Synced() {
+		t.Fatal("update before snapshot must be ignored")
+	}
+	b.apply(&bookMessage{
+		Type:     "snapshot",
+		Sequence: 5,
+		Bids:     []PriceLevel{{MustDecimal("100"), MustDecimal("1")}, {MustDecimal("99.5"), MustDecimal("2")}},
+		Asks:     []PriceLevel{{MustDecimal("101"), MustDecimal("1")}, {MustDecimal("102"), MustDecimal("3")}},
+	})
+	b.apply(&bookMessage{
+		Type:     "update",
+		Sequence: 6,
+		Bids:     []PriceLevel{{MustDecimal("100.0"), MustDecimal("0")}},
+		Asks:     []PriceLevel{{MustDecimal("100.5"), MustDecimal("0.25")}},
+	})
+	if bid, ok := b.BestBid(); !ok || bid.Price.String() != "99.5" {
+		t.Errorf("unexpected best bid %+v", bid)
+	}
+	if ask, ok := b.BestAsk(); !ok || ask.Price.String() != "100.5" {
+		t.Errorf("unexpected best ask %+v", ask)
+	}
+	if _, asks := b.Depth(0); len(asks) != 3 {
+		t.Errorf("unexpected depth %d", len(asks))
+	}
+	select {
+	case <-b.Changes():
+	default:
+		t.Error("no change notification")
+	}
+	if err := b.apply(&bookMessage{Type: "update", Sequence: 8}); err == nil || b.Synced() {
+		t.Error("sequence gap not detected")
+	}
+}
+
+func TestMarketStreamResync(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn := acceptWebsocket(t, w, r)
+		defer conn.conn.Close()
+		if _, data, err := conn.ReadMessage(); err != nil || !strings.Contains(string(data), `"market":"btc-mxn"`) {
+			t.Errorf("unexpected subscription %s %v", data, err)
+			return
+		}
+		conn.WriteMessage(wsOpText, []byte(`{"type":"snapshot","market":"BTC-MXN","sequence":1,"bids":[{"price":"100","amount":"1"}],"asks":[{"price":"110","amount":"1"}]}`))
+		conn.WriteMessage(wsOpText, []byte(`{"type":"update","market":"BTC-MXN","sequence":3,"bids":[{"price":"105","amount":"1"}]}`))
+		if _, data, err := conn.ReadMessage(); err != nil || !strings.Contains(string(data), `"action":"snapshot"`) {
+			t.Errorf("expected resync request, got %s %v", data, err)
+			return
+		}
+		conn.WriteMessage(wsOpText, []byte(`{"type":"snapshot","market":"BTC-MXN","sequence":10,"bids":[{"price":"104","amount":"2"}],"asks":[{"price":"106","amount":"1"}]}`))
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+	s, err := NewMarketStream("btc-mxn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.URL = "ws" + strings.TrimPrefix(srv.URL, "http")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go s.Run(ctx)
+	for {
+		select {
+		case <-s.Book().Changes():
+		case <-ctx.Done():
+			t.Fatal("book not resynced")
+		}
+		if s.Book().Sequence() == 10 {
+			break
+		}
+	}
+	bid, _ := s.Book().BestBid()
+	ask, _ := s.Book().BestAsk()
+	if !s.Book().Synced() || bid.Price.String() != "104" || ask.Price.String() != "106" {
+		t.Errorf("unexpected book after resync: %+v %+v", bid, ask)
+	}
+}
+
+func TestMarketStreamZeroConfig(t *testing.T) {
+	var connections int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&connections, 1)
+		acceptWebsocket(t, w, r).conn.Close() //drop every connection
+	}))
+	defer srv.Close()
+	s := &MarketStream{URL: "ws" + strings.TrimPrefix(srv.URL, "http"), book: NewOrderBook("BTC-MXN")}
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if err := s.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("unexpected Run error %v", err)
+	}
+	if n := atomic.LoadInt32(&connections); n < 1 || n > 5 {
+		t.Errorf("%d connections without the default backoff", n)
+	}
+}
@@ -0,0 +1,205 @@
+package taurosapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// normalize fills the fields Tauros leaves out depending on the endpoint: the
+// ID is set from OrderID, a missing Status is inferred from IsOpen, ClosedAt
+// and the filled amount, or set to OrderStatusUnknown. AveragePrice stays zero
+// when avg_price is not sent: the limit price is not what the fills paid
+func (o *Order) normalize() {
+	if o.ID == 0 {
+		o.ID = o.OrderID
+	}
+	if o.OrderID == 0 {
+		o.OrderID = o.ID
+	}
+	if o.Status == "" {
+		switch {
+		case o.IsOpen && o.Filled.Sign() > 0:
+			o.Status = OrderStatusPartial
+		case o.IsOpen:
+			o.Status = OrderStatusOpen
+		case o.Filled.Sign() > 0 && o.Amount.IsZero():
+			o.Status = OrderStatusFilled
+		case o.isOpenSent || !o.ClosedAt.IsZero():
+			o.Status = OrderStatusCancelled
+		default: //without is_open, whether the order is still on the book is unknown
+			o.Status = OrderStatusUnknown
+		}
+	}
+	if o.Status.Validate() == nil {
+		o.IsOpen = o.Status == OrderStatusOpen || o.Status == OrderStatusPartial
+	}
+}
+
+// UnmarshalJSON - decode an order, remembering whether is_open was sent
+func (o *Order) UnmarshalJSON(data []byte) error {
+	type order Order
+	aux := struct {
+		*order
+		IsOpen *bool `json:"is_open"`
+	}{order: (*order)(o)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	o.isOpenSent = aux.IsOpen != nil
+	o.IsOpen = aux.IsOpen != nil && *aux.IsOpen
+	return nil
+}
+
+// OrderFilter - criteria of GetOrderHistory, zero fields do not filter
+type OrderFilter struct {
+	Market Symbol
+	Side   Side
+	Status []OrderStatus // any of these statuses
+	From   time.Time     // created at or after
+	To     time.Time     // created before
+	Limit  int           // maximum number of orders returned, 0 for the server default
+	Offset int           // orders to skip, for paging
+}
+
+// query encodes the filter as the parameters of the order history endpoint
+func (f OrderFilter) query() url.Values {
+	q := url.Values{}
+	if f.Market != "" {
+		q.Set("market", f.Market.param())
+	}
+	if f.Side != "" {
+		q.Set("side", string(f.Side))
+	}
+	for _, s := range f.Status {
+		q.Add("status", string(s))
+	}
+	if !f.From.IsZero() {
+		q.Set("start_date", f.From.UTC().Format(time.RFC3339))
+	}
+	if !f.To.IsZero() {
+		q.Set("end_date", f.To.UTC().Format(time.RFC3339))
+	}
+	if f.Limit > 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	if f.Offset > 0 {
+		q.Set("offset", strconv.Itoa(f.Offset))
+	}
+	return q
+}
+
+// validate checks and normalises the market, side and statuses of the filter
+func (f *OrderFilter) validate() error {
+	if f.Market != "" {
+		sym, err := ParseSymbol(string(f.Market))
+		if err != nil {
+			return err
+		}
+		f.Market = sym
+	}
+	if f.Side != "" {
+		side, err := ParseSide(string(f.Side))
+		if err != nil {
+			return err
+		}
+		f.Side = side
+	}
+	for _, s := range f.Status {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return fmt.Errorf("order filter ends (%s) before it starts (%s)", f.To, f.From)
+	}
+	return nil
+}
+
+// match reports whether o satisfies the filter; the history is also filtered
+// locally so a criteria ignored by the server cannot leak other orders
+func (f OrderFilter) match(o *Order) bool {
+	if f.Market != "" && o.Market != f.Market {
+		return false
+	}
+	if f.Side != "" && o.Side != f.Side {
+		return false
+	}
+	if len(f.Status) > 0 {
+		found := false
+		for _, s := range f.Status {
+			found = found || o.Status == s
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.From.IsZero() && o.CreatedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !o.CreatedAt.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// GetOrder - get one order of the user, open or closed, with its status, filled amount, average price and fees
+func (t *TauAPI) GetOrder(orderID int64) (Order, error) {
+	return t.GetOrderContext(context.Background(), orderID)
+}
+
+// GetOrderContext - GetOrder honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetOrderContext(ctx context.Context, orderID int64) (Order, error) {
+	var o Order
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
+		Version:   1,
+		Method:    "GET",
+		Path:      "trading/myorders/" + strconv.FormatInt(orderID, 10),
+		NeedsAuth: true,
+	})
+	if err != nil {
+		return o, fmt.Errorf("GetOrder-> %w", err)
+	}
+	if err := json.Unmarshal(jsonData, &o); err != nil {
+		return o, fmt.Errorf("GetOrder-> unmarshal jsonData %w", err)
+	}
+	o.normalize()
+	return o, nil
+}
+
+// GetOrderHistory - get the orders of the user matching filter, most recent first
+func (t *TauAPI) GetOrderHistory(filter OrderFilter) ([]Order, error) {
+	return t.GetOrderHistoryContext(context.Background(), filter)
+}
+
+// GetOrderHistoryContext - GetOrderHistory honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetOrderHistoryContext(ctx context.Context, filter OrderFilter) ([]Order, error) {
+	if err := filter.validate(); err != nil {
+		return nil, fmt.Errorf("GetOrderHistory-> %w", err)
+	}
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
+		Version:   1,
+		Method:    "GET",
+		Path:      "trading/myorders",
+		Query:     filter.query(),
+		NeedsAuth: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetOrderHistory-> %w", err)
+	}
+	var all []Order
+	if err := json.Unmarshal(jsonData, &all); err != nil {
+		return nil, fmt.Errorf("GetOrderHistory-> unmarshal jsonData %w", err)
+	}
+	orders := all[:0]
+	for i := range all {
+		all[i].normalize()
+		if filter.match(&all[i]) {
+			orders = append(orders, all[i])
+		}
+	}
+	return orders, nil
+}
@@ -0,0 +1,88 @@
+package taurosapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestOrderNormalize(t *testing.T) {
+	tests := []struct {
+		order Order
+		want  OrderStatus
+	}{
+		{Order{OrderID: 1, IsOpen: true, Amount: MustDecimal("1")}, OrderStatusOpen},
+		{Order{OrderID: 1, IsOpen: true, Amount: MustDecimal("0.5"), Filled: MustDecimal("0.5")}, OrderStatusPartial},
+		{Order{OrderID: 1, Filled: MustDecimal("1")}, OrderStatusFilled},
+		{Order{OrderID: 1, Amount: MustDecimal("0.5"), Filled: MustDecimal("0.5")}, OrderStatusUnknown},
+		{Order{OrderID: 1}, OrderStatusUnknown},
+		{Order{OrderID: 1, Amount: MustDecimal("0.5"), isOpenSent: true}, OrderStatusCancelled},
+		{Order{OrderID: 1, Status: "expired"}, "expired"}, //kept as sent
+		{Order{OrderID: 1, IsOpen: true, Status: OrderStatusFilled}, OrderStatusFilled},
+	}
+	for i, tt := range tests {
+		tt.order.Price = MustDecimal("100")
+		tt.order.normalize()
+		if tt.order.Status != tt.want || tt.order.ID != 1 {
+			t.Errorf("%d: got status %q id %d, want %q", i, tt.order.Status, tt.order.ID, tt.want)
+		}
+		if tt.order.IsOpen != (tt.want == OrderStatusOpen || tt.want == OrderStatusPartial) {
+			t.Errorf("%d: IsOpen %v for %q", i, tt.order.IsOpen, tt.want)
+		}
+		if !tt.order.AveragePrice.IsZero() {
+			t.Errorf("%d: average price %s not sent but set", i, tt.order.AveragePrice)
+		}
+	}
+}
+
+func TestGetOrderHistory(t *testing.T) {
+	signer, _ := NewSigner("key", "c2VjcmV0")
+	var gotQuery string
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, _ := signer.VerifyRequest(r); !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/trading/myorders/":
+			gotQuery = r.URL.RawQuery
+			w.Write([]byte(`{"success":true,"data":[
+				{"order_id":3,"market":"btc-mxn","side":"sell","amount":"0","filled":"1","price":"100","status":"filled","created_at":"2020-01-03T00:00:00Z"},
+				{"order_id":2,"market":"btc-mxn","side":"sell","amount":"1","filled":"0","price":"100","status":"canceled","created_at":"2020-01-02T00:00:00Z"},
+				{"order_id":1,"market":"eth-mxn","side":"sell","amount":"0","filled":"1","price":"100","status":"filled","created_at":"2020-01-02T00:00:00Z"}
+			]}`))
+		case "/api/v1/trading/myorders/2/":
+			w.Write([]byte(`{"success":true,"data":{"id":2,"market":"btc-mxn","side":"buy","initial_amount":"1","amount":"0.4","filled":"0.6","avg_price":"99.5","fee_amount_paid":"0.001","is_open":false}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	orders, err := c.GetOrderHistory(OrderFilter{
+		Market: "btc_mxn",
+		Side:   SideSell,
+		Status: []OrderStatus{OrderStatusFilled},
+		From:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotQuery != "market=btc-mxn&side=sell&start_date=2020-01-01T00%3A00%3A00Z&status=filled" {
+		t.Errorf("unexpected query %s", gotQuery)
+	}
+	if len(orders) != 1 || orders[0].ID != 3 || orders[0].Status != OrderStatusFilled {
+		t.Errorf("history not filtered: %+v", orders)
+	}
+
+	o, err := c.GetOrder(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Status != OrderStatusCancelled || !o.Filled.Equal(MustDecimal("0.6")) || !o.AveragePrice.Equal(MustDecimal("99.5")) || !o.FeeAmountPaid.Equal(MustDecimal("0.001")) {
+		t.Errorf("unexpected order %+v", o)
+	}
+
+	if _, err := c.GetOrderHistory(OrderFilter{Status: []OrderStatus{"expired"}}); err == nil {
+		t.Error("expected error for invalid status filter")
+	}
+}
@@ -0,0 +1,220 @@
+package taurosapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrPolicyViolation - matched by every PolicyError through errors.Is
+var ErrPolicyViolation = errors.New("tauros: outgoing funds refused by policy")
+
+// Kinds of outgoing movements checked by a Policy
+const (
+	OutgoingTransfer   = "transfer"
+	OutgoingWithdrawal = "withdrawal"
+)
+
+// Outgoing - movement of funds out of the account, checked by a Policy before it is signed
+type Outgoing struct {
+	Kind        string // OutgoingTransfer or OutgoingWithdrawal
+	Coin        string
+	Amount      Decimal
+	Destination string // recipient email of a transfer, address of a withdrawal
+	Tag         string // destination tag or memo of a withdrawal
+}
+
+// PolicyRule - rule of a Policy that refused an Outgoing
+type PolicyRule string
+
+// Policy rules
+const (
+	RuleAmount         PolicyRule = "amount" // the amount is not positive
+	RuleAllowlist      PolicyRule = "allowlist"
+	RulePerTransaction PolicyRule = "per_transaction"
+	RuleDailyLimit     PolicyRule = "daily_limit"
+	RuleApproval       PolicyRule = "approval"
+)
+
+// PolicyError - an outgoing movement was refused by the Policy of the client, nothing was sent
+type PolicyError struct {
+	Rule     PolicyRule
+	Outgoing Outgoing
+	Limit    Decimal // per-transaction or daily limit of the coin
+	Used     Decimal // amount already sent in the last 24 hours, for RuleDailyLimit
+	Err      error   // error returned by the approval hook, for RuleApproval
+}
+
+func (e *PolicyError) Error() string {
+	o := e.Outgoing
+	msg := fmt.Sprintf("policy refused %s of %s %s to %s", o.Kind, o.Amount, o.Coin, o.Destination)
+	switch e.Rule {
+	case RuleAmount:
+		return msg + ": amount must be positive"
+	case RuleAllowlist:
+		return msg + ": destination not in the allowlist"
+	case RulePerTransaction:
+		return fmt.Sprintf("%s: over the per-transaction limit of %s", msg, e.Limit)
+	case RuleDailyLimit:
+		return fmt.Sprintf("%s: %s already sent in the last 24h, limit is %s", msg, e.Used, e.Limit)
+	case RuleApproval:
+		return fmt.Sprintf("%s: not approved: %v", msg, e.Err)
+	}
+	return msg
+}
+
+// Is - match ErrPolicyViolation
+func (e *PolicyError) Is(target error) bool {
+	return target == ErrPolicyViolation
+}
+
+// Unwrap - the error of the approval hook
+func (e *PolicyError) Unwrap() error {
+	return e.Err
+}
+
+// Policy - checks every Transfer and Withdraw of a client before it is signed
+//
+// Zero fields do not restrict anything. Coins are matched case insensitively,
+// and so are emails and 0x addresses in the allowlist. The amounts allowed
+// count towards the rolling 24 hours limit unless the API refused them; a
+// Policy must not be copied once in use.
+type Policy struct {
+	Allowlist      []string           // recipients and addresses funds may be sent to, empty allows any
+	PerTransaction map[string]Decimal // coin -> largest amount of one movement
+	Daily          map[string]Decimal // coin -> largest total over a rolling 24 hours window
+
+	// Approve is called last for every movement the other rules allow, e.g. to
+	// ask a human; a non nil error refuses the movement
+	Approve func(ctx context.Context, o Outgoing) error
+
+	now func() time.Time // time.Now, replaced in tests
+
+	mu     sync.Mutex
+	sent   []policySpend
+	nextID int64
+}
+
+// policySpend - amount counted towards the daily limit of a coin
+type policySpend struct {
+	id     int64
+	at     time.Time
+	coin   string
+	amount Decimal
+}
+
+// WithPolicy - check outgoing transfers and withdrawals against p before they are signed
+func WithPolicy(p *Policy) Option {
+	return func(cfg *clientConfig) { cfg.policy = p }
+}
+
+func coinLimit(limits map[string]Decimal, coin string) (Decimal, bool) {
+	for c, limit := range limits {
+		if strings.EqualFold(c, coin) {
+			return limit, true
+		}
+	}
+	return Decimal{}, false
+}
+
+func (p *Policy) allowed(destination string) bool {
+	if len(p.Allowlist) == 0 {
+		return true
+	}
+	for _, a := range p.Allowlist {
+		fold := strings.Contains(a, "@") || strings.HasPrefix(a, "0x")
+		if a == destination || (fold && strings.EqualFold(a, destination)) {
+			return true
+		}
+	}
+	return false
+}
+
+// check applies the rules to o and, when they allow it, counts o towards the
+// daily limit; the returned release undoes that for movements the API refused
+func (p *Policy) check(ctx context.Context, o Outgoing) (release func(), err error) {
+	if o.Amount.Sign() <= 0 { //a negative amount would lower the daily total
+		return nil, &PolicyError{Rule: RuleAmount, Outgoing: o}
+	}
+	if !p.allowed(o.Destination) {
+		return nil, &PolicyError{Rule: RuleAllowlist, Outgoing: o}
+	}
+	if limit, ok := coinLimit(p.PerTransaction, o.Coin); ok && o.Amount.GreaterThan(limit) {
+		return nil, &PolicyError{Rule: RulePerTransaction, Outgoing: o, Limit: limit}
+	}
+	id, err := p.reserve(o)
+	if err != nil {
+		return nil, err
+	}
+	release = func() { p.release(id) }
+	if p.Approve != nil {
+		if err := p.Approve(ctx, o); err != nil {
+			release()
+			return nil, &PolicyError{Rule: RuleApproval, Outgoing: o, Err: err}
+		}
+	}
+	return release, nil
+}
+
+// reserve counts o towards the daily limit of its coin if it fits
+func (p *Policy) reserve(o Outgoing) (id int64, err error) {
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	at := now()
+	kept := p.sent[:0]
+	used := Decimal{}
+	for _, s := range p.sent {
+		if at.Sub(s.at) >= 24*time.Hour {
+			continue
+		}
+		kept = append(kept, s)
+		if strings.EqualFold(s.coin, o.Coin) {
+			used = used.Add(s.amount)
+		}
+	}
+	p.sent = kept
+	if limit, ok := coinLimit(p.Daily, o.Coin); ok && used.Add(o.Amount).GreaterThan(limit) {
+		return 0, &PolicyError{Rule: RuleDailyLimit, Outgoing: o, Limit: limit, Used: used}
+	}
+	p.nextID++
+	p.sent = append(p.sent, policySpend{id: p.nextID, at: at, coin: o.Coin, amount: o.Amount})
+	return p.nextID, nil
+}
+
+func (p *Policy) release(id int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range p.sent {
+		if p.sent[i].id == id {
+			p.sent = append(p.sent[:i], p.sent[i+1:]...)
+			return
+		}
+	}
+}
+
+// checkPolicy runs o through the policy of the client, if any; call release
+// with the error of the request so movements refused by the API or never sent
+// are not counted
+func (t *TauAPI) checkPolicy(ctx context.Context, o Outgoing) (release func(error), err error) {
+	if t.policy == nil {
+		return func(error) {}, nil
+	}
+	undo, err := t.policy.check(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	return func(reqErr error) {
+		var apiErr *APIError
+		var notSent *notSentError
+		if errors.As(reqErr, &notSent) || errors.As(reqErr, &apiErr) && !apiErr.Temporary() {
+			undo()
+		}
+	}, nil
+}
@@ -0,0 +1,127 @@
+package taurosapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPolicyRules(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	var approvals int
+	p := &Policy{
+		Allowlist:      []string{"Treasury@example.com", "0xABCDEF0000000000000000000000000000000000"},
+		PerTransaction: map[string]Decimal{"btc": MustDecimal("1")},
+		Daily:          map[string]Decimal{"BTC": MustDecimal("1.5")},
+		Approve: func(ctx context.Context, o Outgoing) error {
+			approvals++
+			if o.Destination != "treasury@example.com" {
+				return errors.New("only treasury transfers are approved at night")
+			}
+			return nil
+		},
+		now: func() time.Time { return now },
+	}
+	out := func(dest, amount string) Outgoing {
+		return Outgoing{Kind: OutgoingTransfer, Coin: "BTC", Amount: MustDecimal(amount), Destination: dest}
+	}
+	rule := func(err error) PolicyRule {
+		var perr *PolicyError
+		if !errors.As(err, &perr) || !errors.Is(err, ErrPolicyViolation) {
+			return ""
+		}
+		return perr.Rule
+	}
+	ctx := context.Background()
+
+	for _, amount := range []string{"0", "-5"} {
+		if _, err := p.check(ctx, out("treasury@example.com", amount)); rule(err) != RuleAmount {
+			t.Errorf("%s: expected amount error, got %v", amount, err)
+		}
+	}
+	if _, err := p.check(ctx, out("mallory@example.com", "0.1")); rule(err) != RuleAllowlist {
+		t.Errorf("expected allowlist error, got %v", err)
+	}
+	if _, err := p.check(ctx, out("treasury@example.com", "1.1")); rule(err) != RulePerTransaction {
+		t.Errorf("expected per-transaction error, got %v", err)
+	}
+	if _, err := p.check(ctx, out("treasury@example.com", "0.8")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.check(ctx, out("0xabcdef0000000000000000000000000000000000", "0.3")); rule(err) != RuleApproval {
+		t.Errorf("expected approval error, got %v", err)
+	}
+	release, err := p.check(ctx, out("treasury@example.com", "0.6"))
+	if err != nil {
+		t.Fatalf("refused approval must not count towards the daily limit: %v", err)
+	}
+	var perr *PolicyError
+	if _, err := p.check(ctx, out("treasury@example.com", "0.2")); !errors.As(err, &perr) || perr.Rule != RuleDailyLimit || !perr.Used.Equal(MustDecimal("1.4")) {
+		t.Errorf("expected daily limit error with 1.4 used, got %v", err)
+	}
+	release()
+	if _, err := p.check(ctx, out("treasury@example.com", "0.2")); err != nil {
+		t.Errorf("released amount still counted: %v", err)
+	}
+	now = now.Add(24 * time.Hour)
+	if _, err := p.check(ctx, out("treasury@example.com", "0.9")); err != nil {
+		t.Errorf("window did not roll: %v", err)
+	}
+	if approvals != 5 {
+		t.Errorf("approval hook called %d times, want 5", approvals)
+	}
+}
+
+func TestTransferPolicy(t *testing.T) {
+	var sent int32
+	status := http.StatusOK
+	policy := &Policy{Daily: map[string]Decimal{"MXN": MustDecimal("100")}}
+	c, srv := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sent, 1)
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte(`{"success":true,"payload":{}}`))
+		} else {
+			w.Write([]byte(`{"success":false,"msg":"Insufficient funds"}`))
+		}
+	}), WithPolicy(policy))
+	transfer := TransferMsg{Coin: "MXN", Recipient: "a@example.com", Amount: MustDecimal("60")}
+
+	if _, err := c.Transfer(transfer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Transfer(transfer); !errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("expected policy error, got %v", err)
+	}
+	if atomic.LoadInt32(&sent) != 1 {
+		t.Errorf("refused transfer was sent")
+	}
+
+	transfer.Amount = MustDecimal("40")
+	status = http.StatusBadRequest
+	if _, err := c.Transfer(transfer); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("expected insufficient funds, got %v", err)
+	}
+	status = http.StatusOK
+	if _, err := c.Transfer(transfer); err != nil {
+		t.Errorf("transfer refused by the API still counted: %v", err)
+	}
+
+	policy.now = func() time.Time { return time.Now().Add(25 * time.Hour) } //next day
+	transfer.Amount = MustDecimal("60")
+	c.URL = "http://127.0.0.1:1" //refused connection: the transfer is never sent
+	if _, err := c.Transfer(transfer); err == nil || errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("expected a connection error, got %v", err)
+	}
+	c.URL = srv.URL
+	if _, err := c.Transfer(transfer); err != nil {
+		t.Errorf("transfer never sent still counted: %v", err)
+	}
+
+	if _, err := c.Withdraw("ETH", "0x32Be343B94f860124dC4fEe278FDCBD38C102D88", MustDecimal("1"), WithdrawOptions{}); errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("withdrawal without limits refused: %v", err)
+	}
+}
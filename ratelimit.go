@@ -0,0 +1,132 @@
+package taurosapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Default budgets of the rate limiters installed by NewClient, or on first use
+const (
+	DefaultPublicRate   = 10 // public requests per second
+	DefaultPublicBurst  = 20
+	DefaultPrivateRate  = 5 // signed requests per second
+	DefaultPrivateBurst = 10
+)
+
+// RateLimiter - token bucket shared by every goroutine using the same TauAPI
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64 // bucket capacity
+	tokens float64
+	last   time.Time
+	stats  RateLimiterStats
+}
+
+// RateLimiterStats - how much callers have been throttled by a RateLimiter
+type RateLimiterStats struct {
+	Requests  int64         // calls to Wait
+	Delayed   int64         // calls that had to wait for a token
+	TotalWait time.Duration // sum of the waits
+	MaxWait   time.Duration // longest single wait
+}
+
+// NewRateLimiter - limiter allowing perSecond requests on average with bursts of up to burst requests
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// WithRateLimit - throttle public and signed requests with the given limiters, nil disables the limit
+func WithRateLimit(public, private *RateLimiter) Option {
+	return func(cfg *clientConfig) {
+		cfg.publicLimiter = public
+		cfg.privateLimiter = private
+		cfg.limitersSet = true
+	}
+}
+
+// Wait - block until a request may be sent or ctx is done, returning how long the caller waited
+func (l *RateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	if l == nil || l.rate <= 0 {
+		return 0, nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait > 0 {
+		if err := sleepContext(ctx, wait); err != nil {
+			l.mu.Lock()
+			l.tokens++ //give back the reserved token
+			l.mu.Unlock()
+			return 0, err
+		}
+	}
+	l.mu.Lock()
+	l.stats.Requests++
+	if wait > 0 {
+		l.stats.Delayed++
+		l.stats.TotalWait += wait
+		if wait > l.stats.MaxWait {
+			l.stats.MaxWait = wait
+		}
+	}
+	l.mu.Unlock()
+	return wait, nil
+}
+
+// Stats - snapshot of the throttling done so far
+func (l *RateLimiter) Stats() RateLimiterStats {
+	if l == nil {
+		return RateLimiterStats{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// RateLimitStats - throttling done by the public and the signed request limiters
+func (t *TauAPI) RateLimitStats() (public, private RateLimiterStats) {
+	pub, priv := t.limiters()
+	return pub.Stats(), priv.Stats()
+}
+
+// limiters returns the limiters of the client, installing the default ones
+// in a TauAPI not built by NewClient (e.g. decoded from a tokens file)
+func (t *TauAPI) limiters() (public, private *RateLimiter) {
+	t.limiterMu.Lock()
+	defer t.limiterMu.Unlock()
+	if !t.limitersSet {
+		t.publicLimiter = NewRateLimiter(DefaultPublicRate, DefaultPublicBurst)
+		t.privateLimiter = NewRateLimiter(DefaultPrivateRate, DefaultPrivateBurst)
+		t.limitersSet = true
+	}
+	return t.publicLimiter, t.privateLimiter
+}
+
+func (t *TauAPI) limiter(tauReq *TauReq) *RateLimiter {
+	public, private := t.limiters()
+	if tauReq.NeedsAuth {
+		return private
+	}
+	return public
+}
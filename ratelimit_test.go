@@ -0,0 +1,64 @@
+package taurosapi
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	l := NewRateLimiter(100, 2)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if wait, err := l.Wait(ctx); err != nil || wait != 0 {
+			t.Fatalf("burst request %d waited %s: %v", i, wait, err)
+		}
+	}
+	wait, err := l.Wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait <= 0 || wait > 20*time.Millisecond {
+		t.Errorf("unexpected wait %s", wait)
+	}
+	stats := l.Stats()
+	if stats.Requests != 3 || stats.Delayed != 1 || stats.TotalWait != wait {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestRateLimiterCanceled(t *testing.T) {
+	l := NewRateLimiter(0.001, 1)
+	l.Wait(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRateLimitSeparateBudgets(t *testing.T) {
+	public, private := NewRateLimiter(1000, 1), NewRateLimiter(1000, 1)
+	c, _ := NewClient("key", "c2VjcmV0", WithTransport(&failingTransport{}), WithRetryPolicy(NoRetry), WithRateLimit(public, private))
+	c.GetCoins()
+	c.GetBalances()
+	c.GetBalances()
+	if public.Stats().Requests != 1 || private.Stats().Requests != 2 {
+		t.Errorf("unexpected budgets public=%+v private=%+v", public.Stats(), private.Stats())
+	}
+}
+
+func TestRateLimitDefaultsWithoutNewClient(t *testing.T) {
+	c := &TauAPI{URL: "http://127.0.0.1:1"}
+	public, private := c.limiters()
+	if public == nil || private == nil || public == private {
+		t.Fatal("default limiters not installed")
+	}
+	if p, _ := c.limiters(); p != public {
+		t.Error("limiters installed twice")
+	}
+	disabled, _ := NewClient("key", "c2VjcmV0", WithRateLimit(nil, nil))
+	if p, q := disabled.limiters(); p != nil || q != nil {
+		t.Error("WithRateLimit(nil, nil) did not disable the limits")
+	}
+}
@@ -0,0 +1,131 @@
+package taurosapi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy - how failed requests are retried
+//
+// Idempotent (GET) requests are retried on network errors, 5xx and 429
+// responses. Any other method is retried only when the request provably
+// never reached the server, i.e. the connection failed before the request
+// headers were written, so PlaceOrder or Transfer are never sent twice.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first one, 1 or less disables retries
+	BaseDelay   time.Duration // delay before the first retry, doubled on each further attempt
+	MaxDelay    time.Duration // upper bound of the backoff delay
+}
+
+// DefaultRetryPolicy - policy used when none is configured
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    3 * time.Second,
+}
+
+// NoRetry - policy that never retries
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// WithRetryPolicy - retry failed requests according to p (default DefaultRetryPolicy)
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(cfg *clientConfig) { cfg.retryPolicy = &p }
+}
+
+func (t *TauAPI) retry() RetryPolicy {
+	if t.retryPolicy != nil {
+		return *t.retryPolicy
+	}
+	return DefaultRetryPolicy
+}
+
+// backoff returns the exponential backoff with full jitter before the given
+// retry (1 for the first retry), never less than what Retry-After asked for
+func (p RetryPolicy) backoff(retry int, retryAfter time.Duration) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < retry && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d > 0 {
+		d = time.Duration(jitter(int64(d)))
+	}
+	if retryAfter > d {
+		d = retryAfter
+	}
+	return d
+}
+
+// shouldRetry tells whether the failed attempt (1 based) may be retried and
+// how long to wait before doing so
+func (p RetryPolicy) shouldRetry(method string, attempt int, err error, sent bool) (time.Duration, bool) {
+	var local *buildError
+	if attempt >= p.MaxAttempts || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.As(err, &local) {
+		return 0, false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if !apiErr.Temporary() || !isIdempotent(method) {
+			return 0, false
+		}
+		return p.backoff(attempt, apiErr.RetryAfter), true
+	}
+	if sent && !isIdempotent(method) {
+		return 0, false
+	}
+	return p.backoff(attempt, 0), true
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an http date
+func parseRetryAfter(h string) time.Duration {
+	if h == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(h); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	if at, err := http.ParseTime(h); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func jitter(n int64) int64 {
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return jitterRand.Int63n(n) + 1
+}
+
+// sleepContext waits d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package taurosapi
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestRetryIdempotent(t *testing.T) {
+	var calls int32
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"success":true,"data":{"wallets":[{"coin":"BTC"}]}}`))
+	}), WithRetryPolicy(fastRetry))
+	balances, err := c.GetBalances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(balances) != 1 || calls != 3 {
+		t.Errorf("expected success on third attempt, got %d balances after %d calls", len(balances), calls)
+	}
+}
+
+func TestNoRetryNonIdempotentAfterSend(t *testing.T) {
+	var calls int32
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}), WithRetryPolicy(fastRetry))
+	if err := c.CloseOrder(1); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("POST was sent %d times", calls)
+	}
+}
+
+// failingTransport fails every request, with a dial error unless err is set
+type failingTransport struct {
+	calls int32
+	err   error
+}
+
+func (f *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	atomic.AddInt32(&f.calls, 1)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+}
+
+func TestRetryNonIdempotentNotSent(t *testing.T) {
+	rt := &failingTransport{}
+	c, _ := NewClient("key", "c2VjcmV0", WithTransport(rt), WithRetryPolicy(fastRetry))
+	if err := c.CloseOrder(1); err == nil {
+		t.Fatal("expected error")
+	}
+	if rt.calls != 3 {
+		t.Errorf("expected 3 attempts for a request never dialed, got %d", rt.calls)
+	}
+
+	//a proxy failing after it forwarded the request: the trace cannot tell
+	rt = &failingTransport{err: errors.New("upstream closed the connection")}
+	c, _ = NewClient("key", "c2VjcmV0", WithTransport(rt), WithRetryPolicy(fastRetry))
+	if err := c.CloseOrder(1); err == nil {
+		t.Fatal("expected error")
+	}
+	if rt.calls != 1 {
+		t.Errorf("POST through a custom transport was sent %d times", rt.calls)
+	}
+}
+
+func TestNoRetryLocalErrors(t *testing.T) {
+	slow := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	rt := &failingTransport{}
+	c, _ := NewClient("key", "c2VjcmV0", WithBaseURL("http://[::1"), WithTransport(rt), WithRetryPolicy(slow))
+	if _, err := c.GetBalances(); err == nil {
+		t.Error("expected error for a bad url")
+	}
+	bad := &TauAPI{APIKey: "key", APISecret: "not base64!", URL: "http://127.0.0.1:1", retryPolicy: &slow}
+	if _, err := bad.GetBalances(); err == nil {
+		t.Error("expected error for a bad secret")
+	}
+	if rt.calls != 0 {
+		t.Errorf("requests sent: %d", rt.calls)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for retry := 1; retry < 6; retry++ {
+		if d := p.backoff(retry, 0); d <= 0 || d > time.Second {
+			t.Errorf("backoff %d out of range: %s", retry, d)
+		}
+	}
+	if d := p.backoff(1, 2*time.Second); d != 2*time.Second {
+		t.Errorf("Retry-After not honoured: %s", d)
+	}
+	if d := parseRetryAfter("7"); d != 7*time.Second {
+		t.Errorf("unexpected Retry-After %s", d)
+	}
+}
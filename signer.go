@@ -0,0 +1,93 @@
+package taurosapi
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// Signer - signs requests with the Tauros scheme: base64(HMAC-SHA512(secret, SHA256(nonce + method + path + body)))
+type Signer struct {
+	APIKey string
+	secret []byte // decoded api secret
+}
+
+// NewSigner - signer for the given credentials, the secret must be the base64 string shown by Tauros
+func NewSigner(apiKey, apiSecret string) (*Signer, error) {
+	secret, err := base64.StdEncoding.DecodeString(apiSecret)
+	if err != nil {
+		return nil, fmt.Errorf("NewSigner-> api secret is not valid base64: %w", err)
+	}
+	return &Signer{APIKey: apiKey, secret: secret}, nil
+}
+
+// Sign - signature of the request; path includes the /api/vN/ prefix and query string, an empty body is signed as "{}"
+func (s *Signer) Sign(method, path string, body []byte, nonce string) string {
+	signature, _, _ := s.sign(method, path, body, nonce)
+	return signature
+}
+
+func (s *Signer) sign(method, path string, body []byte, nonce string) (signature, message string, messageHash [32]byte) {
+	postMsg := string(body)
+	if postMsg == "" {
+		postMsg = "{}"
+	}
+	message = nonce + method + path + postMsg
+	messageHash = sha256.Sum256([]byte(message))
+	h := hmac.New(sha512.New, s.secret)
+	h.Write(messageHash[:])
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), message, messageHash
+}
+
+// Verify - check a signature produced by Sign in constant time
+func (s *Signer) Verify(method, path string, body []byte, nonce, signature string) bool {
+	expected := s.Sign(method, path, body, nonce)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// SignRequest - set the Authorization, Taur-Nonce and Taur-Signature headers of req
+func (s *Signer) SignRequest(req *http.Request, nonce int64) error {
+	body, err := peekBody(req)
+	if err != nil {
+		return fmt.Errorf("SignRequest-> %w", err)
+	}
+	n := strconv.FormatInt(nonce, 10)
+	req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	req.Header.Set("Taur-Nonce", n)
+	req.Header.Set("Taur-Signature", s.Sign(req.Method, req.URL.RequestURI(), body, n))
+	return nil
+}
+
+// VerifyRequest - check the Taur-Signature header of a request signed with SignRequest
+func (s *Signer) VerifyRequest(req *http.Request) (bool, error) {
+	body, err := peekBody(req)
+	if err != nil {
+		return false, fmt.Errorf("VerifyRequest-> %w", err)
+	}
+	return s.Verify(req.Method, req.URL.RequestURI(), body, req.Header.Get("Taur-Nonce"), req.Header.Get("Taur-Signature")), nil
+}
+
+// Fingerprint - SecretFingerprint of the signing secret
+func (s *Signer) Fingerprint() string {
+	return SecretFingerprint(s.secret)
+}
+
+// peekBody reads the body of req and puts it back so it can still be sent
+func peekBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
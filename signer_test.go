@@ -0,0 +1,54 @@
+package taurosapi
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestSignerKnownVectors(t *testing.T) {
+	s, err := NewSigner("key", "c2VjcmV0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method, path, body, want string
+	}{
+		{"POST", "/api/v1/trading/placeorder/", `{"market":"BTC-MXN"}`, "GozVibDMf0vf5jxhnSxNgrRgio1m+UnnaXWu1wPbgpT6oVSPXuuhKwz2DPjqfYBZ186jBLhoZCCgu1uUwgs6Yw=="},
+		{"GET", "/api/v1/data/listbalances/", "", "27e5aJkdcfJgLR5z0DaJRnq1kShhbyisDDl68hwRSsW4oo8mkPOmpuKjubGK8hqw1R31MDuqDEuVWceHVc8nnw=="},
+	}
+	for _, tt := range tests {
+		if got := s.Sign(tt.method, tt.path, []byte(tt.body), "1600000000000"); got != tt.want {
+			t.Errorf("%s %s: got %s, want %s", tt.method, tt.path, got, tt.want)
+		}
+		if !s.Verify(tt.method, tt.path, []byte(tt.body), "1600000000000", tt.want) {
+			t.Errorf("%s %s: signature not verified", tt.method, tt.path)
+		}
+		if s.Verify(tt.method, tt.path, []byte(tt.body), "1600000000001", tt.want) {
+			t.Errorf("%s %s: signature verified with another nonce", tt.method, tt.path)
+		}
+	}
+}
+
+func TestSignRequest(t *testing.T) {
+	s, _ := NewSigner("key", "c2VjcmV0")
+	body := `{"market":"BTC-MXN"}`
+	req, _ := http.NewRequest("POST", "https://api.tauros.io/api/v1/trading/placeorder/", bytes.NewBufferString(body))
+	if err := s.SignRequest(req, 1600000000000); err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.Get("Taur-Signature") != "GozVibDMf0vf5jxhnSxNgrRgio1m+UnnaXWu1wPbgpT6oVSPXuuhKwz2DPjqfYBZ186jBLhoZCCgu1uUwgs6Yw==" {
+		t.Errorf("unexpected signature %s", req.Header.Get("Taur-Signature"))
+	}
+	if req.Header.Get("Authorization") != "Bearer key" || req.Header.Get("Taur-Nonce") != "1600000000000" {
+		t.Errorf("unexpected headers %v", req.Header)
+	}
+	if ok, err := s.VerifyRequest(req); err != nil || !ok {
+		t.Errorf("request not verified: %v", err)
+	}
+	var buf bytes.Buffer
+	buf.ReadFrom(req.Body)
+	if buf.String() != body {
+		t.Errorf("body not restored: %q", buf.String())
+	}
+}
@@ -2,18 +2,19 @@ package taurosapi
 
 import (
 	"bytes"
-	"crypto/hmac"
-	"crypto/sha256"
-	"crypto/sha512"
-	"encoding/base64"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
-	"math"
+	"net"
 	"net/http"
-	"net/http/httputil"
+	"net/http/httptrace"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -23,114 +24,65 @@ type TauAPI struct {
 	APISecret string `json:"api_secret"`
 	URL       string `json:"url"`
 	Email     string `json:"email"`
-}
 
-// TauWsObject - Tauros Websocket message "object"
-type TauWsObject struct {
-	Amount         string `json:"amount"`
-	AmountPaid     string `json:"amount_paid"`
-	AmountReceived string `json:"amount_received"`
-	ClosedAt       string `json:"closed_at"`
-	CreatedAt      string `json:"created_at"`
-	FeeAmountPaid  string `json:"fee_amount_paid"`
-	FeeDecimal     string `json:"fee_decimal"`
-	FeePercent     string `json:"fee_percent"`
-	Filled         string `json:"filled"`
-	ID             int64  `json:"id"`
-	InitialAmount  string `json:"initial_amount"`
-	InitialValue   string `json:"initial_value"`
-	IsOpen         bool   `json:"is_open"`
-	LeftCoin       string `json:"left_coin"`
-	Market         string `json:"market"`
-	Price          string `json:"price"`
-	RightCoin      string `json:"right_coin"`
-	Side           string `json:"side"`
-	Value          string `json:"value"`
-}
-
-// TauWebHookObject - Taures Webhook message "object"
-type TauWebHookObject struct {
-	Market              string `json:"market"`
-	Side                string `json:"side"`
-	InitialAmount       string `json:"initial_amount"`
-	Filled              string `json:"filled"`
-	Value               string `json:"value"`
-	InitialValue        string `json:"initial_value"`
-	Price               string `json:"price"`
-	FeeDecimal          string `json:"fee_decimal"` //todo: issue to correct too much data and overlapping names
-	FeePercent          string `json:"fee_percent"`
-	FeeAmountPaid       string `json:"fee_amount_paid"`
-	IsOpen              bool   `json:"is_open"`
-	AmountPaid          string `json:"amount_paid"`
-	AmountReceived      string `json:"amount_received"`
-	CreatedAt           string `json:"created_at"`
-	ClosedAt            string `json:"closed_at"`
-	LeftCoin            string `json:"left_coin"`
-	RightCoin           string `json:"right_coin"`
-	LeftCoinIcon        string `json:"left_coin_icon"`
-	RightCoinIcon       string `json:"right_coin_icon"`
-	Sender              string `json:"sender"`
-	Receiver            string `json:"receiver"`
-	Coin                string `json:"coin"`
-	CoinName            string `json:"coin_name"`
-	CoinIcon            string `json:"coin_icon"`
-	Amount              string `json:"amount"`
-	TxID                string `json:"txId"` //todo: github issue correcting json format to "tx_id"
-	Confirmed           bool   `json:"confirmed"`
-	ConfirmedAt         string `json:"confirmed_at"`
-	IsInnerTransfer     bool   `json:"is_innerTransfer"` //todo: issue to correct json name to is_inner_transfer
-	Address             string `json:"address"`
-	ExplorerLink        string `json:"explorer_link"`
-	FeeAmount           string `json:"fee_amount"`
-	TotalAmount         string `json:"total_amount"`
-	Type                string `json:"type"`
-	Description         string `json:"description"`
-	TradeAmountPaid     string `json:"trade_amount_paid"`     //the actual amounts of the trade
-	TradeAmountReceived string `json:"trade_amount_received"` // in order filled messages
-	ID                  int64  `json:"id"`
-	TransactionType     string `json:"transaction_type"`
-	DateTime            string `json:"datetime"`
+	httpClient  *http.Client
+	userAgent   string
+	debugLogger Logger
+	retryPolicy *RetryPolicy
+
+	limiterMu      sync.Mutex
+	publicLimiter  *RateLimiter
+	privateLimiter *RateLimiter
+	limitersSet    bool // false until the limiters are installed, which may be nil
+	nonceSource    NonceSource
+
+	signerMu     sync.Mutex
+	signer       *Signer
+	signerKey    string // credentials the signer was built from
+	signerSecret string
+
+	policy *Policy
+
+	transferMu         sync.Mutex
+	transfersInFlight  map[string]bool      // references with a Transfer call running
+	transfersUnsettled map[string]time.Time // references failed ambiguously, until when they are not sent again
 }
 
 // NewOrder - new order data
 type NewOrder struct {
-	Market        string `json:"market"`
-	Side          string `json:"side"`
-	Amount        string `json:"amount"`
-	Type          string `json:"type"`
-	Price         string `json:"price"`
-	IsAmountValue bool   `json:"is_amount_value,omitempty"`
-}
-
-// TauWsMessage - Tauros Websocket message header
-type TauWsMessage struct {
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	Type        string      `json:"type"`
-	Date        string      `json:"date"`
-	Object      TauWsObject `json:"object"`
+	Market        Symbol    `json:"market"`
+	Side          Side      `json:"side"`
+	Amount        Decimal   `json:"amount"`
+	Type          OrderType `json:"type"`
+	Price         Decimal   `json:"price"`
+	IsAmountValue bool      `json:"is_amount_value,omitempty"`
 }
 
-// TauWebHookMessage - Tauros POST message received via webhooks
-type TauWebHookMessage struct { //todo: unify this with TauWsMessage
-	Title       string           `json:"title"`
-	Description string           `json:"description"`
-	Type        string           `json:"type"`
-	Date        string           `json:"date"`
-	Object      TauWebHookObject `json:"object"`
+// MarshalJSON - omit the price when it is not set, as for market orders, and send the market lowercase
+func (o NewOrder) MarshalJSON() ([]byte, error) {
+	type newOrder NewOrder
+	var price *Decimal
+	if !o.Price.IsZero() {
+		price = &o.Price
+	}
+	o.Market = Symbol(o.Market.param())
+	return json.Marshal(struct {
+		newOrder
+		Price *Decimal `json:"price,omitempty"`
+	}{newOrder(o), price})
 }
 
 // Message - main message struct
 type Message struct {
-	ID            int64  `json:"id,omitempty"`
-	Market        string `json:"market,omitempty"`
-	Amount        string `json:"amount,omitempty"`
-	Side          string `json:"side,omitempty"`
-	Type          string `json:"type,omitempty"`
-	Price         string `json:"price,omitempty"`
-	IsAmountValue bool   `json:"is_amount_value,omitempty"`
-	Email         string `json:"email,omitempty"`
-	Password      string `json:"password,omitempty"`
+	ID            int64    `json:"id,omitempty"`
+	Market        string   `json:"market,omitempty"`
+	Amount        *Decimal `json:"amount,omitempty"`
+	Side          string   `json:"side,omitempty"`
+	Type          string   `json:"type,omitempty"`
+	Price         *Decimal `json:"price,omitempty"`
+	IsAmountValue bool     `json:"is_amount_value,omitempty"`
+	Email         string   `json:"email,omitempty"`
+	Password      string   `json:"password,omitempty"`
 }
 
 // TransferMsg - json for direct Tauros Transfer
@@ -138,23 +90,34 @@ type TransferMsg struct {
 	Nip       string  `json:"nip"`
 	Coin      string  `json:"coin"`
 	Recipient string  `json:"recipient"`
-	Amount    float64 `json:"amount"`
+	Amount    Decimal `json:"amount"`
+	Reference string  `json:"description,omitempty"` //optional client reference making Transfer idempotent
 }
 
 // Order - order message struct
 type Order struct {
-	ID            int64       `json:"id"`       //for PlaceOrder
-	OrderID       int64       `json:"order_id"` //for GetOpenOrders
-	Market        string      `json:"market"`
-	Side          string      `json:"side"`
-	Amount        json.Number `json:"amount,Number"`
-	InitialAmount json.Number `json:"initial_amount,Number"`
-	Filled        json.Number `json:"filled,Number"`
-	Value         json.Number `json:"value,Number"`
-	InitialValue  json.Number `json:"initial_value,Number"`
-	Price         json.Number `json:"price,Number"`
-	FeeDecimal    json.Number `json:"fee_decimal"`
-	CreatedAt     string      `json:"created_at"`
+	ID            int64     `json:"id"`       //for PlaceOrder
+	OrderID       int64     `json:"order_id"` //for GetOpenOrders
+	Market        Symbol    `json:"market"`
+	Side          Side      `json:"side"`
+	Amount        Decimal   `json:"amount"`
+	InitialAmount Decimal   `json:"initial_amount"`
+	Filled        Decimal   `json:"filled"`
+	Value         Decimal   `json:"value"`
+	InitialValue  Decimal   `json:"initial_value"`
+	Price         Decimal   `json:"price"`
+	FeeDecimal    Decimal   `json:"fee_decimal"`
+	CreatedAt     Timestamp `json:"created_at"`
+
+	//filled in by GetOrder and GetOrderHistory, see Order.normalize
+	Type          OrderType   `json:"type"`
+	Status        OrderStatus `json:"status"`
+	IsOpen        bool        `json:"is_open"`
+	AveragePrice  Decimal     `json:"avg_price"` // zero when not sent
+	FeeAmountPaid Decimal     `json:"fee_amount_paid"`
+	ClosedAt      Timestamp   `json:"closed_at"`
+
+	isOpenSent bool // is_open was part of the response, so false means closed
 }
 
 // MarketOrders - market orders (bids and asks) struct
@@ -162,17 +125,17 @@ type MarketOrders struct {
 	Market string `json:"market"`
 	Asks   []Order
 	Bids   []Order
-	MinAsk float64
-	MaxBid float64
+	MinAsk Decimal
+	MaxBid Decimal
 }
 
 // Coin - available coins
 type Coin struct {
-	Coin                  string      `json:"coin"`
-	MinWithdrawal         json.Number `json:"min_withdraw"`
-	FeeWithdrawal         json.Number `json:"fee_withdraw"`
-	Country               string      `json:"country"`
-	ConfirmationsRequired int         `json:"confirmations_required"`
+	Coin                  string  `json:"coin"`
+	MinWithdrawal         Decimal `json:"min_withdraw"`
+	FeeWithdrawal         Decimal `json:"fee_withdraw"`
+	Country               string  `json:"country"`
+	ConfirmationsRequired int     `json:"confirmations_required"`
 }
 
 // Balance - available balances
@@ -181,61 +144,67 @@ type Balance struct {
 	CoinName string `json:"coin_name"`
 	Address  string `json:"address"`
 	Balances struct {
-		Available json.Number `json:"available"`
-		Pending   json.Number `json:"pending"`
-		Frozen    json.Number `json:"frozen"`
-		InOrders  json.Number `json:"in_orders"`
+		Available Decimal `json:"available"`
+		Pending   Decimal `json:"pending"`
+		Frozen    Decimal `json:"frozen"`
+		InOrders  Decimal `json:"in_orders"`
 	} `json:"balances"`
 }
 
 // Webhook - data of a Webhook
 type Webhook struct {
-	ID                   int64  `json:"id"`
-	Name                 string `json:"name"`
-	Endpoint             string `json:"endpoint"`
-	NotifyDeposit        bool   `json:"notify_deposit"`
-	NotifyWithdrawal     bool   `json:"notify_withdrawal"`
-	NotifyOrderPlaced    bool   `json:"notify_order_place"`
-	NotifyOrderFilled    bool   `json:"notify order_filled"`
-	NotifyTrade          bool   `json:"notify_trade"`
-	AuthorizationHeader  string `json:"authorization_header"`
-	AuthorizationContent string `json:"authorization_content"`
-	IsActive             bool   `json:"is_active"`
-	CreatedAt            string `json:"created_at"`
-	UpdatedAt            string `json:"updated_at"`
-	Detail               string `json:"detail"`
+	ID                   int64     `json:"id"`
+	Name                 string    `json:"name"`
+	Endpoint             string    `json:"endpoint"`
+	NotifyDeposit        bool      `json:"notify_deposit"`
+	NotifyWithdrawal     bool      `json:"notify_withdrawal"`
+	NotifyOrderPlaced    bool      `json:"notify_order_placed"`
+	NotifyOrderFilled    bool      `json:"notify_order_filled"`
+	NotifyTrade          bool      `json:"notify_trade"`
+	AuthorizationHeader  string    `json:"authorization_header"`
+	AuthorizationContent string    `json:"authorization_content"`
+	IsActive             bool      `json:"is_active"`
+	CreatedAt            Timestamp `json:"created_at"`
+	UpdatedAt            Timestamp `json:"updated_at"`
+	Detail               string    `json:"detail"`
 }
 
 // Market - data of a market
 type Market struct {
-	Name      string      `json:"name"`
-	MinAmount json.Number `json:"min_amount"`
-	MaxAmount json.Number `json:"max_amount"`
-	MinValue  json.Number `json:"min_value"`
-	MaxValue  json.Number `json:"max_value"`
-	MinPrice  json.Number `json:"min_price"`
-	MaxPrice  json.Number `json:"max_price"`
-	IsOpen    bool        `json:"is_open"`
+	Name      string  `json:"name"`
+	MinAmount Decimal `json:"min_amount"`
+	MaxAmount Decimal `json:"max_amount"`
+	MinValue  Decimal `json:"min_value"`
+	MaxValue  Decimal `json:"max_value"`
+	MinPrice  Decimal `json:"min_price"`
+	MaxPrice  Decimal `json:"max_price"`
+	IsOpen    bool    `json:"is_open"`
 }
 
-//TauReq - request parameters
+// TauReq - request parameters
 type TauReq struct {
 	Version   int
 	Method    string
 	Path      string
+	Query     url.Values //appended to the path after the trailing slash, and signed with it
 	NeedsAuth bool
 	PostMsg   []byte
 }
 
 // GetWebhooks - get all the registered webhooks
 func (t *TauAPI) GetWebhooks() (webhooks []Webhook, error error) {
+	return t.GetWebhooksContext(context.Background())
+}
+
+// GetWebhooksContext - GetWebhooks honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetWebhooksContext(ctx context.Context) (webhooks []Webhook, error error) {
 	var w = []Webhook{}
 	var d struct {
 		Count    int64     `json:"count"`
 		Webhooks []Webhook `json:"results"`
 		Detail   string    `json:"detail"`
 	}
-	jsonData, err := t.doTauRequest(&TauReq{
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
 		Version:   2,
 		Method:    "GET",
 		Path:      "webhooks/webhooks",
@@ -247,40 +216,73 @@ func (t *TauAPI) GetWebhooks() (webhooks []Webhook, error error) {
 	if err := json.Unmarshal(jsonData, &d); err != nil {
 		return w, err
 	}
-	if d.Detail == "Invalid token." { //todo: really use http status code instead.
-		return w, fmt.Errorf("Tauros API: %s", d.Detail)
+	if d.Detail != "" { //webhook endpoints may report errors with a 200 status
+		return w, &APIError{
+			StatusCode: http.StatusOK,
+			Method:     "GET",
+			Path:       "webhooks/webhooks/",
+			Version:    2,
+			Message:    d.Detail,
+			Body:       jsonData,
+		}
 	}
 	return d.Webhooks, nil
 }
 
-// CreateWebhook - add a webhook
-func (t *TauAPI) CreateWebhook(webhook Webhook) (ID int64, error error) {
-	jsonPostMsg, _ := json.Marshal(webhook)
-	jsonData, err := t.doTauRequest(&TauReq{
+// CreateWebhook - add a webhook and return it as registered by Tauros; when the
+// account already has MaxWebhooks the error is a *WebhookLimitError
+func (t *TauAPI) CreateWebhook(webhook Webhook) (Webhook, error) {
+	return t.CreateWebhookContext(context.Background(), webhook)
+}
+
+// CreateWebhookContext - CreateWebhook honouring the cancellation and deadline of ctx
+func (t *TauAPI) CreateWebhookContext(ctx context.Context, webhook Webhook) (Webhook, error) {
+	tauReq := &TauReq{
 		Version:   2,
 		Method:    "POST",
 		Path:      "webhooks/webhooks",
 		NeedsAuth: true,
-		PostMsg:   jsonPostMsg,
-	})
-	if err != nil {
-		return 0, nil
 	}
-	if string(jsonData) == "[\"Limit reached\"]" {
-		return 0, fmt.Errorf("Limit of webhooks reached (5)")
+	tauReq.PostMsg, _ = json.Marshal(webhook)
+	jsonData, err := t.doTauRequest(ctx, tauReq)
+	if err == nil && len(jsonData) > 0 && jsonData[0] == '[' { //refusals may come as a list of messages with a 200 status
+		err = newAPIError(tauReq, http.StatusOK, jsonData)
 	}
-	var d struct {
-		ID int64 `json:"id"`
+	if errors.Is(err, ErrWebhookLimit) {
+		limitErr := &WebhookLimitError{Count: -1, Max: MaxWebhooks, Err: err}
+		if registered, listErr := t.GetWebhooksContext(ctx); listErr == nil {
+			limitErr.Count = len(registered)
+		}
+		return Webhook{}, limitErr
 	}
-	if err := json.Unmarshal(jsonData, &d); err != nil {
-		return 0, fmt.Errorf("CreateWebhook -> unmarshal jsonData %v", err)
+	if err != nil {
+		return Webhook{}, err
+	}
+	var created Webhook
+	if err := json.Unmarshal(jsonData, &created); err != nil {
+		return Webhook{}, fmt.Errorf("CreateWebhook -> unmarshal jsonData %w", err)
+	}
+	if created.Detail != "" {
+		return Webhook{}, &APIError{
+			StatusCode: http.StatusOK,
+			Method:     tauReq.Method,
+			Path:       tauReq.Path,
+			Version:    2,
+			Message:    created.Detail,
+			Body:       jsonData,
+		}
 	}
-	return d.ID, nil
+	return created, nil
 }
 
 // DeleteWebhook - delete one webhook according to the webhook ID
 func (t *TauAPI) DeleteWebhook(ID int64) error {
-	_, err := t.doTauRequest(&TauReq{
+	return t.DeleteWebhookContext(context.Background(), ID)
+}
+
+// DeleteWebhookContext - DeleteWebhook honouring the cancellation and deadline of ctx
+func (t *TauAPI) DeleteWebhookContext(ctx context.Context, ID int64) error {
+	_, err := t.doTauRequest(ctx, &TauReq{
 		Version:   2,
 		Method:    "DELETE",
 		Path:      "webhooks/webhooks/" + strconv.FormatInt(ID, 10),
@@ -291,12 +293,17 @@ func (t *TauAPI) DeleteWebhook(ID int64) error {
 
 // DeleteWebhooks - delete all currently registered webhooks
 func (t *TauAPI) DeleteWebhooks() (error error) {
-	webhooks, err := t.GetWebhooks()
+	return t.DeleteWebhooksContext(context.Background())
+}
+
+// DeleteWebhooksContext - DeleteWebhooks honouring the cancellation and deadline of ctx
+func (t *TauAPI) DeleteWebhooksContext(ctx context.Context) (error error) {
+	webhooks, err := t.GetWebhooksContext(ctx)
 	if err != nil {
 		return err
 	}
 	for _, w := range webhooks {
-		err := t.DeleteWebhook(w.ID)
+		err := t.DeleteWebhookContext(ctx, w.ID)
 		if err != nil {
 			return err
 		}
@@ -304,14 +311,84 @@ func (t *TauAPI) DeleteWebhooks() (error error) {
 	return nil
 }
 
+// GetWebhook - get one webhook according to the webhook ID
+func (t *TauAPI) GetWebhook(ID int64) (Webhook, error) {
+	return t.GetWebhookContext(context.Background(), ID)
+}
+
+// GetWebhookContext - GetWebhook honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetWebhookContext(ctx context.Context, ID int64) (Webhook, error) {
+	return t.webhookRequest(ctx, "GET", ID, nil)
+}
+
+// UpdateWebhook - replace the settings of the webhook ID, e.g. to rotate its authorization content
+func (t *TauAPI) UpdateWebhook(ID int64, webhook Webhook) (Webhook, error) {
+	return t.UpdateWebhookContext(context.Background(), ID, webhook)
+}
+
+// UpdateWebhookContext - UpdateWebhook honouring the cancellation and deadline of ctx
+func (t *TauAPI) UpdateWebhookContext(ctx context.Context, ID int64, webhook Webhook) (Webhook, error) {
+	webhook.ID = ID
+	jsonPostMsg, _ := json.Marshal(webhook)
+	return t.webhookRequest(ctx, "PUT", ID, jsonPostMsg)
+}
+
+// SetWebhookActive - pause (false) or resume (true) the notifications of the webhook ID, keeping its registration
+func (t *TauAPI) SetWebhookActive(ID int64, active bool) (Webhook, error) {
+	return t.SetWebhookActiveContext(context.Background(), ID, active)
+}
+
+// SetWebhookActiveContext - SetWebhookActive honouring the cancellation and deadline of ctx
+func (t *TauAPI) SetWebhookActiveContext(ctx context.Context, ID int64, active bool) (Webhook, error) {
+	jsonPostMsg, _ := json.Marshal(struct {
+		IsActive bool `json:"is_active"`
+	}{active})
+	return t.webhookRequest(ctx, "PATCH", ID, jsonPostMsg)
+}
+
+// webhookRequest sends method to the endpoint of the webhook ID and decodes the webhook returned
+func (t *TauAPI) webhookRequest(ctx context.Context, method string, ID int64, postMsg []byte) (Webhook, error) {
+	path := "webhooks/webhooks/" + strconv.FormatInt(ID, 10)
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
+		Version:   2,
+		Method:    method,
+		Path:      path,
+		NeedsAuth: true,
+		PostMsg:   postMsg,
+	})
+	if err != nil {
+		return Webhook{}, err
+	}
+	var w Webhook
+	if err := json.Unmarshal(jsonData, &w); err != nil {
+		return Webhook{}, fmt.Errorf("%s webhook -> unmarshal jsonData %w", method, err)
+	}
+	if w.Detail != "" { //webhook endpoints may report errors with a 200 status
+		return Webhook{}, &APIError{
+			StatusCode: http.StatusOK,
+			Method:     method,
+			Path:       path + "/",
+			Version:    2,
+			Message:    w.Detail,
+			Body:       jsonData,
+		}
+	}
+	return w, nil
+}
+
 // GetCoins - get all available coins handled by the exchange
 func (t *TauAPI) GetCoins() (coins []Coin, error error) {
+	return t.GetCoinsContext(context.Background())
+}
+
+// GetCoinsContext - GetCoins honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetCoinsContext(ctx context.Context) (coins []Coin, error error) {
 	var c = []Coin{}
 	var d struct {
 		Crypto []Coin `json:"cryto"` //typo from api
 		Fiat   []Coin `json:"fiat"`
 	}
-	jsonData, err := t.doTauRequest(&TauReq{
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
 		Version: 2,
 		Method:  "GET",
 		Path:    "coins",
@@ -328,48 +405,59 @@ func (t *TauAPI) GetCoins() (coins []Coin, error error) {
 
 // GetMarkets - get current available markets
 func (t *TauAPI) GetMarkets() (markets []Market, error error) {
+	return t.GetMarketsContext(context.Background())
+}
+
+// GetMarketsContext - GetMarkets honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetMarketsContext(ctx context.Context) (markets []Market, error error) {
 	var m []Market
-	jsonData, err := t.doTauRequest(&TauReq{
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
 		Version: 2,
 		Method:  "GET",
 		Path:    "trading/markets",
 	})
 	if err != nil {
-		return nil, fmt.Errorf("TauGetMarkets ->%v", err)
+		return nil, fmt.Errorf("TauGetMarkets ->%w", err)
 	}
 	if err := json.Unmarshal(jsonData, &m); err != nil {
-		return nil, fmt.Errorf("TauGetMarkets json.Unmarshall->%v", err)
+		return nil, fmt.Errorf("TauGetMarkets json.Unmarshall->%w", err)
 	}
 	return m, nil
 }
 
 // GetMarketOrders - get current market orders for one market
-func (t *TauAPI) GetMarketOrders(market string) (MarketOrders, error) {
+func (t *TauAPI) GetMarketOrders(market Symbol) (MarketOrders, error) {
+	return t.GetMarketOrdersContext(context.Background(), market)
+}
+
+// GetMarketOrdersContext - GetMarketOrders honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetMarketOrdersContext(ctx context.Context, market Symbol) (MarketOrders, error) {
 	var mo MarketOrders
-	var maxBid, minAsk float64
-	jsonData, err := t.doTauRequest(&TauReq{
+	var maxBid, minAsk Decimal
+	market, err := ParseSymbol(string(market))
+	if err != nil {
+		return mo, fmt.Errorf("TauGetMarketOrders ->%w", err)
+	}
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
 		Version: 1,
 		Method:  "GET",
-		Path:    "trading/orders?market=" + strings.ToLower(market),
+		Path:    "trading/orders?market=" + market.param(),
 	})
 	if err != nil {
-		return mo, fmt.Errorf("TauGetMarketOrders ->%s", err.Error())
+		return mo, fmt.Errorf("TauGetMarketOrders ->%w", err)
 	}
 	if err := json.Unmarshal(jsonData, &mo); err != nil {
 		return mo, err
 	}
-	maxBid = 0.0
 	for _, b := range mo.Bids {
-		bid, _ := strconv.ParseFloat(b.Price.String(), 64)
-		maxBid = math.Max(bid, maxBid)
+		maxBid = MaxDecimal(b.Price, maxBid)
 	}
 	if len(mo.Asks) == 0 {
-		minAsk = maxBid + 0.01
+		minAsk = maxBid.Add(NewDecimal(1, 2))
 	} else {
-		minAsk, _ = strconv.ParseFloat(mo.Asks[0].Price.String(), 64)
+		minAsk = mo.Asks[0].Price
 		for _, a := range mo.Asks {
-			ask, _ := strconv.ParseFloat(a.Price.String(), 64)
-			minAsk = math.Min(ask, minAsk)
+			minAsk = MinDecimal(a.Price, minAsk)
 		}
 	}
 	mo.MaxBid = maxBid
@@ -379,11 +467,16 @@ func (t *TauAPI) GetMarketOrders(market string) (MarketOrders, error) {
 
 // GetBalances - get available balances of the user
 func (t *TauAPI) GetBalances() (balances []Balance, error error) {
+	return t.GetBalancesContext(context.Background())
+}
+
+// GetBalancesContext - GetBalances honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetBalancesContext(ctx context.Context) (balances []Balance, error error) {
 	var b []Balance
 	var w struct {
 		Wallets []Balance `json:"wallets"`
 	}
-	jsonData, err := t.doTauRequest(&TauReq{
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
 		Version:   1,
 		Method:    "GET",
 		Path:      "data/listbalances",
@@ -400,29 +493,43 @@ func (t *TauAPI) GetBalances() (balances []Balance, error error) {
 
 // GetDepositAddress - get the deposit address of the user for the specified coin
 func (t *TauAPI) GetDepositAddress(coin string) (address string, error error) {
-	jsonData, err := t.doTauRequest(&TauReq{
+	return t.GetDepositAddressContext(context.Background(), coin)
+}
+
+// GetDepositAddressContext - GetDepositAddress honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetDepositAddressContext(ctx context.Context, coin string) (address string, error error) {
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
 		Version:   1,
 		Method:    "GET",
 		Path:      "data/getdepositaddress?coin=" + coin,
 		NeedsAuth: true,
 	})
 	if err != nil {
-		return "", fmt.Errorf("TauDepositAddress-> %v", err)
+		return "", fmt.Errorf("TauDepositAddress-> %w", err)
 	}
 	var d struct {
 		Coin    string `json:"coin"`
 		Address string `json:"address"`
 	}
 	if err := json.Unmarshal(jsonData, &d); err != nil {
-		return "", fmt.Errorf("TauDepositAddress-> %v", err)
+		return "", fmt.Errorf("TauDepositAddress-> %w", err)
 	}
 	return d.Address, nil
 }
 
 // PlaceOrder - add a new order
 func (t *TauAPI) PlaceOrder(newOrder NewOrder) (Order, error) {
+	return t.PlaceOrderContext(context.Background(), newOrder)
+}
+
+// PlaceOrderContext - PlaceOrder honouring the cancellation and deadline of ctx
+func (t *TauAPI) PlaceOrderContext(ctx context.Context, newOrder NewOrder) (Order, error) {
+	newOrder.normalize()
+	if err := newOrder.Validate(); err != nil {
+		return Order{}, fmt.Errorf("PlaceOrder-> %w", err)
+	}
 	jsonPostMsg, _ := json.Marshal(newOrder)
-	jsonData, err := t.doTauRequest(&TauReq{
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
 		Version:   1,
 		Method:    "POST",
 		Path:      "trading/placeorder",
@@ -432,40 +539,54 @@ func (t *TauAPI) PlaceOrder(newOrder NewOrder) (Order, error) {
 	//log.Printf("raw json of placeorder: %s", string(jsonData))
 	var o Order
 	if err != nil {
-		return o, fmt.Errorf("PlaceOrder-> %v", err)
+		return o, fmt.Errorf("PlaceOrder-> %w", err)
 	}
 	if err := json.Unmarshal(jsonData, &o); err != nil {
-		return o, fmt.Errorf("PlaceOrder-> unmarshal jsonData %v", err)
+		return o, fmt.Errorf("PlaceOrder-> unmarshal jsonData %w", err)
 	}
 	return o, nil
 }
 
 // GetOpenOrders - get all open orders by the user
 func (t *TauAPI) GetOpenOrders() (orders []Order, error error) {
-	jsonData, err := t.doTauRequest(&TauReq{
+	return t.GetOpenOrdersContext(context.Background())
+}
+
+// GetOpenOrdersContext - GetOpenOrders honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetOpenOrdersContext(ctx context.Context) (orders []Order, error error) {
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
 		Version:   1,
 		Method:    "GET",
 		Path:      "trading/myopenorders",
 		NeedsAuth: true,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("GetOpenOrders->%v", err)
+		return nil, fmt.Errorf("GetOpenOrders->%w", err)
 	}
 	if err := json.Unmarshal(jsonData, &orders); err != nil {
-		return nil, fmt.Errorf("GetOpenOrders->%v", err)
+		return nil, fmt.Errorf("GetOpenOrders->%w", err)
+	}
+	for i := range orders {
+		orders[i].IsOpen = true
+		orders[i].normalize()
 	}
 	return orders, nil
 }
 
 // CloseAllOrders - close all currently open orders
 func (t *TauAPI) CloseAllOrders() error {
-	orders, err := t.GetOpenOrders()
+	return t.CloseAllOrdersContext(context.Background())
+}
+
+// CloseAllOrdersContext - CloseAllOrders honouring the cancellation and deadline of ctx
+func (t *TauAPI) CloseAllOrdersContext(ctx context.Context) error {
+	orders, err := t.GetOpenOrdersContext(ctx)
 	if err != nil {
-		return fmt.Errorf("CloseAllOrders ->%v", err)
+		return fmt.Errorf("CloseAllOrders ->%w", err)
 	}
 	for _, o := range orders {
-		if err := t.CloseOrder(o.OrderID); err != nil {
-			return fmt.Errorf("CloseAllOrders Deleting Order %d ->%v", o.ID, err)
+		if err := t.CloseOrderContext(ctx, o.OrderID); err != nil {
+			return fmt.Errorf("CloseAllOrders Deleting Order %d ->%w", o.ID, err)
 		}
 	}
 	return nil
@@ -473,8 +594,13 @@ func (t *TauAPI) CloseAllOrders() error {
 
 // CloseOrder - close the order specified by the order ID
 func (t *TauAPI) CloseOrder(orderID int64) error {
+	return t.CloseOrderContext(context.Background(), orderID)
+}
+
+// CloseOrderContext - CloseOrder honouring the cancellation and deadline of ctx
+func (t *TauAPI) CloseOrderContext(ctx context.Context, orderID int64) error {
 	jsonPostMsg, _ := json.Marshal(Message{ID: orderID})
-	_, err := t.doTauRequest(&TauReq{
+	_, err := t.doTauRequest(ctx, &TauReq{
 		Version:   1,
 		Method:    "POST",
 		Path:      "trading/closeorder",
@@ -482,15 +608,20 @@ func (t *TauAPI) CloseOrder(orderID int64) error {
 		PostMsg:   jsonPostMsg,
 	})
 	if err != nil {
-		return fmt.Errorf("CloseOrder->%v", err)
+		return fmt.Errorf("CloseOrder->%w", err)
 	}
 	return nil
 }
 
 // Login - simulate a login to get the jwt token
 func (t *TauAPI) Login(email string, password string) (jwtToken string, err error) {
+	return t.LoginContext(context.Background(), email, password)
+}
+
+// LoginContext - Login honouring the cancellation and deadline of ctx
+func (t *TauAPI) LoginContext(ctx context.Context, email string, password string) (jwtToken string, err error) {
 	jsonPostMsg, _ := json.Marshal(&Message{Email: email, Password: password})
-	jsonData, err := t.doTauRequest(&TauReq{
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
 		Version:   2,
 		Method:    "POST",
 		Path:      "auth/signin",
@@ -498,104 +629,156 @@ func (t *TauAPI) Login(email string, password string) (jwtToken string, err erro
 		PostMsg:   jsonPostMsg,
 	})
 	if err != nil {
-		return "", fmt.Errorf("Login->%v", err)
+		return "", fmt.Errorf("Login->%w", err)
 	}
 	var d struct {
 		Token     string `json:"token"`
 		TwoFactor bool   `json:"two_factor"`
 	}
 	if err := json.Unmarshal(jsonData, &d); err != nil {
-		return "", fmt.Errorf("Login->%v", err)
+		return "", fmt.Errorf("Login->%w", err)
 	}
 	return d.Token, nil
 }
 
-// Transfer - direct transfer of funds to another Tauros account
-func (t *TauAPI) Transfer(transfer TransferMsg) error {
-	jsonPostMsg, _ := json.Marshal(&transfer)
-	_, err := t.doTauRequest(&TauReq{
-		Version:   2,
-		Method:    "POST",
-		Path:      "wallets/inner-transfer",
-		NeedsAuth: true,
-		PostMsg:   jsonPostMsg,
-	})
-	if err != nil {
-		return fmt.Errorf("Transfer->%v", err)
+// debugRejected adds the nonce and secret fingerprint to a rejected signature
+// error, the full signing details only going to the debug logger
+func (t *TauAPI) debugRejected(apiErr *APIError, sigDebug *signatureDebug) {
+	if !errors.Is(apiErr, ErrInvalidToken) && !errors.Is(apiErr, ErrBadSignature) {
+		return
+	}
+	apiErr.debug = sigDebug.short()
+	if t.debugLogger != nil && sigDebug != nil {
+		t.debugLogger.Printf("tauros: rejected %s /api/v%d/%s%s", apiErr.Method, apiErr.Version, apiErr.Path, sigDebug)
 	}
-	return nil //no need to see return post
 }
 
-func (t *TauAPI) doTauRequest(tauReq *TauReq) (msgdata json.RawMessage, e error) {
+func (t *TauAPI) doTauRequest(ctx context.Context, tauReq *TauReq) (msgdata json.RawMessage, e error) {
+	if tauReq.NeedsAuth {
+		tauReq.Path += "/"
+	}
+	policy := t.retry()
+	for attempt := 1; ; attempt++ {
+		msgdata, sent, err := t.doTauRequestOnce(ctx, tauReq)
+		if err == nil {
+			return msgdata, nil
+		}
+		wait, retry := policy.shouldRetry(tauReq.Method, attempt, err, sent)
+		if !retry {
+			if !sent {
+				err = &notSentError{err}
+			}
+			return nil, err
+		}
+		if ctxErr := sleepContext(ctx, wait); ctxErr != nil {
+			err = fmt.Errorf("doTauRequest-> %w (attempt %d failed: %v)", ctxErr, attempt, err)
+			if !sent {
+				err = &notSentError{err}
+			}
+			return nil, err
+		}
+	}
+}
+
+// requestSent reports whether a request that failed with err may have reached
+// the server. The WroteHeaders trace only fires in an *http.Transport: behind
+// any other RoundTripper, e.g. a proxy given to WithTransport, only a dial
+// error proves that nothing was sent.
+func (t *TauAPI) requestSent(wroteHeaders bool, err error) bool {
+	if wroteHeaders {
+		return true
+	}
+	rt := t.client().Transport
+	if _, ok := rt.(*http.Transport); ok || rt == nil {
+		return false
+	}
+	var opErr *net.OpError
+	return !errors.As(err, &opErr) || opErr.Op != "dial"
+}
+
+// buildError wraps a failure to build or sign a request locally, which a retry cannot fix
+type buildError struct {
+	err error
+}
+
+func (e *buildError) Error() string { return e.err.Error() }
+
+func (e *buildError) Unwrap() error { return e.err }
+
+// notSentError wraps the error of a request that never reached the server,
+// so callers know it had no effect
+type notSentError struct {
+	err error
+}
+
+func (e *notSentError) Error() string { return e.err.Error() }
+
+func (e *notSentError) Unwrap() error { return e.err }
+
+// doTauRequestOnce signs and sends one attempt of tauReq, sent reports
+// whether the request may have reached the server
+func (t *TauAPI) doTauRequestOnce(ctx context.Context, tauReq *TauReq) (msgdata json.RawMessage, sent bool, e error) {
 	var httpReq *http.Request
-	var signatureDebugInfo string
+	var sigDebug *signatureDebug
 	var err error
 	apiVersion := fmt.Sprintf("v%1d", tauReq.Version)
-	if tauReq.NeedsAuth {
-		tauReq.Path += "/"
+	reqPath := tauReq.Path
+	if len(tauReq.Query) > 0 {
+		reqPath += "?" + tauReq.Query.Encode()
+	}
+	if _, err := t.limiter(tauReq).Wait(ctx); err != nil {
+		return nil, false, fmt.Errorf("doTauRequest-> rate limiter: %w", err)
 	}
-	httpReq, err = http.NewRequest(tauReq.Method, t.URL+"/api/"+apiVersion+"/"+tauReq.Path, bytes.NewBuffer(tauReq.PostMsg))
-	httpReq.Body.Close()
+	httpReq, err = http.NewRequestWithContext(ctx, tauReq.Method, t.URL+"/api/"+apiVersion+"/"+reqPath, bytes.NewBuffer(tauReq.PostMsg))
 	if err != nil {
-		return nil, fmt.Errorf("doTauRequest-> Error on http.NewRequest: %v", err)
+		return nil, false, &buildError{fmt.Errorf("doTauRequest-> Error on http.NewRequest: %w", err)}
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Accept", "application/json")
+	if t.userAgent != "" {
+		httpReq.Header.Set("User-Agent", t.userAgent)
+	} else {
+		httpReq.Header.Set("User-Agent", DefaultUserAgent)
+	}
 	if tauReq.NeedsAuth {
-
-		var postMsg string
-		var nonce string
-		var path string
-		var message string
-		var messageHash [32]byte
-		var decodedAPISecret []byte
-
-		postMsg = string(tauReq.PostMsg)
-		if postMsg == "" {
-			postMsg = "{}"
-		}
-		nonce = strconv.FormatInt(time.Now().UnixNano()/1e6, 10) //todo: check divide by time.millisecond
-		path = "/api/" + apiVersion + "/" + tauReq.Path          //trailing backslash must be added at each post request in path
-		message = nonce + tauReq.Method + path + postMsg
-		messageHash = sha256.Sum256([]byte(message))
-		if d, err := base64.StdEncoding.DecodeString(t.APISecret); err != nil {
-			return nil, fmt.Errorf("doTauRequest -> Error decoding APiSecret base64: %v", err)
-		} else {
-			decodedAPISecret = d
+		signer, err := t.requestSigner()
+		if err != nil {
+			return nil, false, &buildError{fmt.Errorf("doTauRequest -> %w", err)}
 		}
-		h := hmac.New(sha512.New, decodedAPISecret)
-		h.Write(messageHash[:])
-		signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
-		httpReq.Header.Set("Authorization", "Bearer "+t.APIKey)
+		nonce := strconv.FormatInt(t.nonce(), 10)
+		path := "/api/" + apiVersion + "/" + reqPath //trailing backslash must be added at each post request in path
+		signature, message, messageHash := signer.sign(tauReq.Method, path, tauReq.PostMsg, nonce)
+		httpReq.Header.Set("Authorization", "Bearer "+signer.APIKey)
 		httpReq.Header.Set("Taur-Nonce", nonce)
 		httpReq.Header.Set("Taur-Signature", signature)
 
-		dumpRequest, _ := httputil.DumpRequestOut(httpReq, true)
-		signatureDebugInfo = fmt.Sprintf("\nPost message: %q", string(tauReq.PostMsg)) +
-			fmt.Sprintf("\nPost Message body: %s", postMsg) +
-			fmt.Sprintf("\nNonce=%s", nonce) +
-			fmt.Sprintf("\nmessage: %s", message) +
-			fmt.Sprintf("\nmessageHash: %x", messageHash) +
-			fmt.Sprintf("\nApi_key: %s", t.APIKey) +
-			fmt.Sprintf("\nApi_secret: %s", t.APISecret) +
-			fmt.Sprintf("\nDecoded b64 api_secret: %x", decodedAPISecret) +
-			fmt.Sprintf("\nsignature=%s", signature) +
-			fmt.Sprintf("\nbytes.NewBuffer(tauReq.PostMsg: %s", bytes.NewBuffer(tauReq.PostMsg).String()) +
-			fmt.Sprintf("\nDump Request [%s]\n", dumpRequest)
-	}
-
-	client := http.Client{Timeout: time.Second * 3}
+		sigDebug = newSignatureDebug(nonce, message, messageHash, signer.Fingerprint())
+		if t.debugLogger != nil {
+			t.debugLogger.Printf("tauros: signed %s %s%s", tauReq.Method, path, sigDebug)
+		}
+	}
+
+	var wrote int32 //set by the transport goroutine
+	httpReq = httpReq.WithContext(httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteHeaders: func() { atomic.StoreInt32(&wrote, 1) },
+	}))
 	start := time.Now()
-	resp, err := client.Do(httpReq)
+	resp, err := t.client().Do(httpReq)
+	sent = resp != nil || t.requestSent(atomic.LoadInt32(&wrote) == 1, err)
 	if err != nil {
-		return nil, fmt.Errorf("Elapsed: %s | doTauRequest-> Error reading response: %v", time.Since(start), err)
+		return nil, sent, fmt.Errorf("Elapsed: %s | doTauRequest-> Error reading response: %w", time.Since(start), err)
 	}
 	defer resp.Body.Close()
-	//todo: check StatusCode
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("doTauRequest-> Error ioutil body: %v", err)
+		return nil, sent, fmt.Errorf("doTauRequest-> Error ioutil body: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		apiErr := newAPIError(tauReq, resp.StatusCode, body)
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		t.debugRejected(apiErr, sigDebug)
+		return nil, sent, apiErr
 	}
 	if strings.Contains(tauReq.Path, "webhooks") { //needed because the webhook endpoints are missing this header
 		if len(body) == 0 {
@@ -610,24 +793,18 @@ func (t *TauAPI) doTauRequest(tauReq *TauReq) (msgdata json.RawMessage, e error)
 		Payload json.RawMessage `json:"payload"`
 	}
 	if err := json.Unmarshal(body, &respJSON); err != nil {
-		return nil, fmt.Errorf("doTauRequest-> Unmarshal error: %v \n resp code: %d, body=%s", err, resp.StatusCode, body)
+		return nil, sent, fmt.Errorf("doTauRequest-> Unmarshal error: %w \n resp code: %d, body=%s", err, resp.StatusCode, body)
 	}
 	if !respJSON.Success {
-		msg := string(respJSON.Message)
-		if msg == "" {
-			msg = string(body)
-		}
-		if strings.Contains(msg, "Invalid token") {
-			msg += "Authorization: Bearer " + t.APIKey
-			msg += signatureDebugInfo
-		}
-		if strings.Contains(msg, "signature") {
-			msg += signatureDebugInfo
+		apiErr := newAPIError(tauReq, resp.StatusCode, body)
+		if apiErr.Message == "" {
+			apiErr.Message = string(body)
 		}
-		return nil, fmt.Errorf(msg)
+		t.debugRejected(apiErr, sigDebug)
+		return nil, sent, apiErr
 	}
 	if tauReq.Version == 1 {
-		return respJSON.Data, err
+		return respJSON.Data, sent, err
 	}
-	return respJSON.Payload, err
+	return respJSON.Payload, sent, err
 }
@@ -1,8 +1,8 @@
 package taurosapi
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"log"
 	"strings"
@@ -58,8 +58,8 @@ func TestGetMarketOrders(t *testing.T) {
 	if strings.ToLower(marketOrders.Market) != "btc-mxn" {
 		t.Errorf("returned orders of another market: %s", marketOrders.Market)
 	}
-	log.Printf("Min Ask: %8.2f", marketOrders.MinAsk)
-	log.Printf("Max Bid: %8.2f", marketOrders.MaxBid)
+	log.Printf("Min Ask: %s", marketOrders.MinAsk)
+	log.Printf("Max Bid: %s", marketOrders.MaxBid)
 }
 
 func TestDeleteWebhooks(t *testing.T) {
@@ -69,7 +69,7 @@ func TestDeleteWebhooks(t *testing.T) {
 }
 
 func TestCreateWebhook(t *testing.T) {
-	webhookID, err := tauros.CreateWebhook(Webhook{
+	webhook, err := tauros.CreateWebhook(Webhook{
 		Name:              "MyWebhook",
 		Endpoint:          "https://somendpoint.com",
 		NotifyDeposit:     true,
@@ -83,9 +83,10 @@ func TestCreateWebhook(t *testing.T) {
 		t.Errorf("%v", err)
 		t.SkipNow()
 	}
-	if webhookID == 0 {
+	if webhook.ID == 0 {
 		t.Errorf("expected webhook id to be not zero")
 	}
+	webhookID = webhook.ID
 }
 
 func TestDeleteWebhook(t *testing.T) {
@@ -112,11 +113,11 @@ func TestGetBalances(t *testing.T) {
 }
 
 func TestTransfer(t *testing.T) {
-	err = tauros.Transfer(TransferMsg{
+	_, err = tauros.Transfer(TransferMsg{
 		Recipient: "david@montebit.com",
 		Coin:      "MXN",
 		Nip:       "119744",
-		Amount:    1.99,
+		Amount:    MustDecimal("1.99"),
 	})
 	if err != nil {
 		t.Errorf("%v", err)
@@ -124,23 +125,23 @@ func TestTransfer(t *testing.T) {
 }
 
 func TestPlaceOrder(t *testing.T) {
-	var available float64
+	var available Decimal
 	for _, b := range balances {
 		if b.Coin == "BTC" {
-			available, _ = b.Balances.Available.Float64()
+			available = b.Balances.Available
 			break
 		}
 	}
-	if !(available > 0.001) {
+	if !available.GreaterThan(MustDecimal("0.001")) {
 		t.Log("no BTC balance available to test placeorder func")
 		t.SkipNow()
 	}
 	if order, err = tauros.PlaceOrder(NewOrder{
 		Market: "BTC-MXN",
-		Side:   "sell",
-		Amount: fmt.Sprintf("%.8f", available*0.1),
-		Price:  "250000.0",
-		Type:   "limit",
+		Side:   SideSell,
+		Amount: available.Mul(MustDecimal("0.1")).Truncate(8),
+		Price:  MustDecimal("250000.0"),
+		Type:   OrderTypeLimit,
 	}); err != nil {
 		t.Errorf("Unable to place order: %v", err)
 	}
@@ -160,3 +161,11 @@ func TestCloseOrder(t *testing.T) {
 	// 	t.Errorf("%v", err)
 	// }
 }
+
+func TestGetCoinsContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := tauros.GetCoinsContext(ctx); err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("expected context canceled error, got %v", err)
+	}
+}
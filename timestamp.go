@@ -0,0 +1,65 @@
+package taurosapi
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Timestamp - time sent by Tauros, tolerant of the layouts the API emits;
+// an empty string or null (e.g. closed_at of an open order) decodes as the zero time
+type Timestamp struct {
+	time.Time
+}
+
+// timestampLayouts are tried in order, layouts without zone are read as UTC
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// ParseTimestamp - parse a time in any of the layouts used by Tauros, or unix seconds/milliseconds
+func ParseTimestamp(s string) (Timestamp, error) {
+	if s == "" {
+		return Timestamp{}, nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Timestamp{t}, nil
+		}
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n > 1e12 { //milliseconds
+			return Timestamp{time.Unix(n/1e3, n%1e3*int64(time.Millisecond)).UTC()}, nil
+		}
+		return Timestamp{time.Unix(n, 0).UTC()}, nil
+	}
+	return Timestamp{}, fmt.Errorf("ParseTimestamp-> unknown time format %q", s)
+}
+
+// MarshalJSON - RFC 3339 time, or null for the zero time
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + t.Format(time.RFC3339Nano) + `"`), nil
+}
+
+// UnmarshalJSON - accept any layout known to ParseTimestamp, quoted or bare
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		*t = Timestamp{}
+		return nil
+	}
+	ts, err := ParseTimestamp(string(bytes.Trim(data, `"`)))
+	if err != nil {
+		return err
+	}
+	*t = ts
+	return nil
+}
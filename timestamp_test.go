@@ -0,0 +1,50 @@
+package taurosapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2020, 5, 17, 18, 30, 12, 123456000, time.UTC)
+	for _, in := range []string{
+		"2020-05-17T18:30:12.123456Z",
+		"2020-05-17T13:30:12.123456-05:00",
+		"2020-05-17T18:30:12.123456",
+		"2020-05-17 18:30:12.123456",
+		"2020-05-17 18:30:12.123456+00:00",
+	} {
+		ts, err := ParseTimestamp(in)
+		if err != nil {
+			t.Errorf("%s: %v", in, err)
+			continue
+		}
+		if !ts.Equal(want) {
+			t.Errorf("%s: got %s", in, ts)
+		}
+	}
+	if ts, err := ParseTimestamp("1589740212123"); err != nil || !ts.Equal(want.Truncate(time.Millisecond)) {
+		t.Errorf("unix millis: %s %v", ts, err)
+	}
+	if _, err := ParseTimestamp("yesterday"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestTimestampJSON(t *testing.T) {
+	var o OrderPlacedEvent
+	if err := json.Unmarshal([]byte(`{"created_at":"2020-05-17T18:30:12Z","closed_at":""}`), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.CreatedAt.Year() != 2020 || !o.ClosedAt.IsZero() {
+		t.Errorf("unexpected times %s %s", o.CreatedAt, o.ClosedAt)
+	}
+	out, _ := json.Marshal(struct {
+		A Timestamp `json:"a"`
+		B Timestamp `json:"b"`
+	}{A: o.CreatedAt})
+	if string(out) != `{"a":"2020-05-17T18:30:12Z","b":null}` {
+		t.Errorf("unexpected encode %s", out)
+	}
+}
@@ -0,0 +1,226 @@
+package taurosapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTradePageSize - trades requested per page by GetTrades
+const DefaultTradePageSize = 100
+
+// Trade - one fill of an order of the user
+type Trade struct {
+	ID        int64     `json:"id"`
+	OrderID   int64     `json:"order_id"`
+	Market    Symbol    `json:"market"`
+	Side      Side      `json:"side"`
+	Price     Decimal   `json:"price"`
+	Amount    Decimal   `json:"amount"`
+	Value     Decimal   `json:"value"`
+	Fee       Decimal   `json:"fee_amount"`
+	FeeCoin   string    `json:"fee_coin"`
+	Liquidity Liquidity `json:"liquidity"`
+	IsMaker   bool      `json:"is_maker"` //sent instead of liquidity by some endpoints
+	CreatedAt Timestamp `json:"created_at"`
+}
+
+// normalize fills Liquidity from IsMaker when it was not sent, and the other way round
+func (tr *Trade) normalize() {
+	if tr.Liquidity == "" {
+		tr.Liquidity = LiquidityTaker
+		if tr.IsMaker {
+			tr.Liquidity = LiquidityMaker
+		}
+	}
+	tr.IsMaker = tr.Liquidity == LiquidityMaker
+}
+
+// TradeFilter - criteria of GetTrades, zero fields do not filter
+type TradeFilter struct {
+	Market   Symbol
+	Side     Side
+	OrderID  int64     // fills of this order only
+	From     time.Time // executed at or after
+	To       time.Time // executed before
+	PageSize int       // trades requested per page, default DefaultTradePageSize
+}
+
+func (f TradeFilter) query(page int) url.Values {
+	q := url.Values{}
+	if f.Market != "" {
+		q.Set("market", f.Market.param())
+	}
+	if f.Side != "" {
+		q.Set("side", string(f.Side))
+	}
+	if f.OrderID != 0 {
+		q.Set("order_id", strconv.FormatInt(f.OrderID, 10))
+	}
+	if !f.From.IsZero() {
+		q.Set("start_date", f.From.UTC().Format(time.RFC3339))
+	}
+	if !f.To.IsZero() {
+		q.Set("end_date", f.To.UTC().Format(time.RFC3339))
+	}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("page_size", strconv.Itoa(f.PageSize))
+	return q
+}
+
+// match checks the filter locally, as the server may ignore some of its
+// parameters; fields a trade was sent without are not checked
+func (f TradeFilter) match(tr *Trade) bool {
+	if f.Market != "" && tr.Market != "" && tr.Market != f.Market {
+		return false
+	}
+	if f.Side != "" && tr.Side != "" && !strings.EqualFold(string(tr.Side), string(f.Side)) {
+		return false
+	}
+	if f.OrderID != 0 && tr.OrderID != 0 && tr.OrderID != f.OrderID {
+		return false
+	}
+	if !f.From.IsZero() && !tr.CreatedAt.IsZero() && tr.CreatedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !tr.CreatedAt.IsZero() && !tr.CreatedAt.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// TradeIterator - pages through the trades returned by GetTrades
+//
+//	it := tauros.GetTrades(TradeFilter{Market: "btc-mxn"})
+//	for it.Next() {
+//		trade := it.Trade()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type TradeIterator struct {
+	t      *TauAPI
+	ctx    context.Context
+	filter TradeFilter
+
+	page    int            // last page fetched
+	seen    map[int64]bool // IDs of the trades returned so far
+	buf     []Trade
+	current Trade
+	last    bool // the last page was fetched
+	err     error
+}
+
+// GetTrades - iterate over the fills of the user matching filter, most recent first;
+// pages are requested as the iterator advances
+func (t *TauAPI) GetTrades(filter TradeFilter) *TradeIterator {
+	return t.GetTradesContext(context.Background(), filter)
+}
+
+// GetTradesContext - GetTrades honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetTradesContext(ctx context.Context, filter TradeFilter) *TradeIterator {
+	it := &TradeIterator{t: t, ctx: ctx, filter: filter}
+	if it.filter.PageSize <= 0 {
+		it.filter.PageSize = DefaultTradePageSize
+	}
+	if filter.Market != "" {
+		sym, err := ParseSymbol(string(filter.Market))
+		if err != nil {
+			it.err = fmt.Errorf("GetTrades-> %w", err)
+		}
+		it.filter.Market = sym
+	}
+	if filter.Side != "" {
+		side, err := ParseSide(string(filter.Side))
+		if err != nil {
+			it.err = fmt.Errorf("GetTrades-> %w", err)
+		}
+		it.filter.Side = side
+	}
+	return it
+}
+
+// Next - advance to the next trade, false when there are no more trades or an error occurred
+func (it *TradeIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.err != nil || it.last {
+			return false
+		}
+		it.fetch()
+	}
+	it.current, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Trade - the trade Next advanced to
+func (it *TradeIterator) Trade() Trade {
+	return it.current
+}
+
+// Err - the error that stopped the iteration, nil when every trade was read
+func (it *TradeIterator) Err() error {
+	return it.err
+}
+
+// All - read the remaining trades
+func (it *TradeIterator) All() ([]Trade, error) {
+	var trades []Trade
+	for it.Next() {
+		trades = append(trades, it.Trade())
+	}
+	return trades, it.Err()
+}
+
+// fetch requests the next page; the endpoint answers either a list of trades
+// or a page object with "results" and a "next" link
+func (it *TradeIterator) fetch() {
+	it.page++
+	jsonData, err := it.t.doTauRequest(it.ctx, &TauReq{
+		Version:   1,
+		Method:    "GET",
+		Path:      "trading/mytrades",
+		Query:     it.filter.query(it.page),
+		NeedsAuth: true,
+	})
+	if err != nil {
+		it.err = fmt.Errorf("GetTrades-> page %d: %w", it.page, err)
+		return
+	}
+	var page struct {
+		Results []Trade         `json:"results"`
+		Next    json.RawMessage `json:"next"` //null on the last page
+	}
+	if len(jsonData) > 0 && jsonData[0] == '[' {
+		err = json.Unmarshal(jsonData, &page.Results)
+	} else {
+		err = json.Unmarshal(jsonData, &page)
+	}
+	if err != nil {
+		it.err = fmt.Errorf("GetTrades-> page %d: unmarshal jsonData %w", it.page, err)
+		return
+	}
+	if it.seen == nil {
+		it.seen = make(map[int64]bool)
+	}
+	it.buf = nil
+	fresh := 0
+	for _, trade := range page.Results {
+		trade.normalize()
+		if trade.ID != 0 && it.seen[trade.ID] { //pages overlap when trades arrive while paging
+			continue
+		}
+		it.seen[trade.ID] = true
+		fresh++
+		if it.filter.match(&trade) {
+			it.buf = append(it.buf, trade)
+		}
+	}
+	next := string(page.Next)
+	//a page without new trades means the server ignores the paging parameters
+	it.last = len(page.Results) < it.filter.PageSize || next == "null" || next == `""` || fresh == 0
+}
@@ -0,0 +1,104 @@
+package taurosapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGetTradesPages(t *testing.T) {
+	var pages []string
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		pages = append(pages, q.Get("page"))
+		if q.Get("market") != "btc-mxn" || q.Get("order_id") != "9" || q.Get("page_size") != "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		page, _ := strconv.Atoi(q.Get("page"))
+		switch page {
+		case 1, 2:
+			fmt.Fprintf(w, `{"success":true,"data":{"next":"https://next","results":[
+				{"id":%d,"order_id":9,"price":"100","amount":"0.1","fee_amount":"0.0001","fee_coin":"BTC","liquidity":"maker"},
+				{"id":%d,"order_id":9,"price":"101","amount":"0.2","fee_amount":"0.2","fee_coin":"MXN","is_maker":false}]}}`, page*10, page*10+1)
+		default:
+			w.Write([]byte(`{"success":true,"data":{"next":null,"results":[]}}`))
+		}
+	}))
+
+	trades, err := c.GetTrades(TradeFilter{Market: "btc_mxn", OrderID: 9, PageSize: 2}).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 4 || trades[0].ID != 10 || trades[3].ID != 21 {
+		t.Fatalf("unexpected trades %+v", trades)
+	}
+	if trades[0].Liquidity != LiquidityMaker || trades[1].Liquidity != LiquidityTaker || !trades[0].IsMaker {
+		t.Errorf("liquidity not normalised: %+v", trades[:2])
+	}
+	if trades[1].FeeCoin != "MXN" || !trades[1].Fee.Equal(MustDecimal("0.2")) {
+		t.Errorf("unexpected fee of %+v", trades[1])
+	}
+	if fmt.Sprint(pages) != "[1 2 3]" {
+		t.Errorf("unexpected pages requested %v", pages)
+	}
+}
+
+func TestGetTradesIgnoredPaging(t *testing.T) {
+	var calls int
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"success":true,"data":[{"id":1,"price":"100","amount":"0.1"},{"id":2,"price":"100","amount":"0.1"}]}`))
+	}))
+
+	trades, err := c.GetTrades(TradeFilter{PageSize: 2}).All()
+	if err != nil || len(trades) != 2 || calls != 2 {
+		t.Errorf("got %d trades after %d calls: %v", len(trades), calls, err)
+	}
+}
+
+func TestGetTradesFiltersLocally(t *testing.T) {
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//the filter parameters are ignored
+		w.Write([]byte(`{"success":true,"data":[
+			{"id":1,"order_id":9,"market":"BTC-MXN","side":"buy","created_at":"2020-01-02T00:00:00Z"},
+			{"id":2,"order_id":9,"market":"ETH-MXN","side":"buy","created_at":"2020-01-02T00:00:00Z"},
+			{"id":3,"order_id":9,"market":"BTC-MXN","side":"SELL","created_at":"2020-01-02T00:00:00Z"},
+			{"id":4,"order_id":8,"market":"BTC-MXN","side":"buy","created_at":"2020-01-02T00:00:00Z"},
+			{"id":5,"order_id":9,"market":"BTC-MXN","side":"buy","created_at":"2019-12-31T00:00:00Z"},
+			{"id":6,"order_id":9,"market":"BTC-MXN","side":"buy","created_at":"2020-01-03T00:00:00Z"},
+			{"id":7,"order_id":9,"market":"btc_mxn","side":"BUY","created_at":"2020-01-02T12:00:00Z"}]}`))
+	}))
+
+	filter := TradeFilter{Market: "btc-mxn", Side: "buy", OrderID: 9,
+		From: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)}
+	trades, err := c.GetTrades(filter).All()
+	if err != nil || len(trades) != 2 || trades[0].ID != 1 || trades[1].ID != 7 {
+		t.Errorf("unexpected trades %+v, %v", trades, err)
+	}
+	if it := c.GetTrades(TradeFilter{Side: SideSell}); !it.Next() || it.Trade().ID != 3 || it.Next() {
+		t.Errorf("trade of the other side returned: %+v", it.Trade())
+	}
+}
+
+func TestGetTradesListAndErrors(t *testing.T) {
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success":true,"data":[{"id":1},{"id":2}]}`))
+	}))
+
+	trades, err := c.GetTrades(TradeFilter{}).All()
+	if err != nil || len(trades) != 2 {
+		t.Errorf("short list should be the last page: %v, %v", trades, err)
+	}
+	if it := c.GetTrades(TradeFilter{Side: "long"}); it.Next() || it.Err() == nil {
+		t.Error("expected error for invalid side")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if it := c.GetTradesContext(ctx, TradeFilter{}); it.Next() || it.Err() == nil {
+		t.Error("expected error for canceled context")
+	}
+}
@@ -0,0 +1,323 @@
+package taurosapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrTransferNotFound - no transfer with the reference is in the transfer history
+var ErrTransferNotFound = errors.New("tauros: transfer not found")
+
+// ErrTransferStatusUnknown - matched by a TransferStatusError through errors.Is
+var ErrTransferStatusUnknown = errors.New("tauros: transfer status unknown")
+
+// ErrTransferInProgress - another call of Transfer with the same reference is running
+var ErrTransferInProgress = errors.New("tauros: transfer with the reference in progress")
+
+// ErrTransferUnsettled - an earlier transfer with the reference failed ambiguously
+// and is not in the history yet, so it is not sent again before TransferSettlePeriod
+var ErrTransferUnsettled = errors.New("tauros: earlier transfer with the reference not settled")
+
+// TransferSettlePeriod - how long after an ambiguous failure a transfer with the
+// same reference is answered with a *TransferStatusError instead of being sent
+// again, while waiting for it to show up in the history
+const TransferSettlePeriod = 10 * time.Minute
+
+// TransferStatus - state of a transfer between Tauros accounts
+type TransferStatus string
+
+// Transfer statuses
+const (
+	TransferCompleted TransferStatus = "completed"
+	TransferPending   TransferStatus = "pending"
+)
+
+// TransferResult - outcome of Transfer
+type TransferResult struct {
+	ID        int64          `json:"id"`
+	Status    TransferStatus `json:"status"`
+	Reference string         `json:"-"`
+
+	// Duplicate is true when a transfer with the same reference was already
+	// in the history, so nothing was sent
+	Duplicate bool `json:"-"`
+}
+
+// TransferStatusError - the transfer request failed in a way that does not
+// tell whether the funds moved (timeout, connection lost, server error) and
+// the transfer could not be found in the history either; retry with the same
+// Reference, which checks the history again before sending anything
+type TransferStatusError struct {
+	Reference string
+	Err       error
+}
+
+func (e *TransferStatusError) Error() string {
+	if e.Reference == "" {
+		return fmt.Sprintf("transfer status unknown, no reference to look it up: %v", e.Err)
+	}
+	return fmt.Sprintf("transfer %q status unknown: %v", e.Reference, e.Err)
+}
+
+// Is - match ErrTransferStatusUnknown
+func (e *TransferStatusError) Is(target error) bool {
+	return target == ErrTransferStatusUnknown
+}
+
+// Unwrap - the error of the transfer request
+func (e *TransferStatusError) Unwrap() error {
+	return e.Err
+}
+
+// Transfer - direct transfer of funds to another Tauros account, checked against
+// the Policy of the client (see WithPolicy) before it is signed
+//
+// With a Reference the transfer is idempotent: the transfer history is
+// checked before sending, so a transfer already made with that reference is
+// returned as a Duplicate instead of being paid again. A transfer is sent at
+// most once per call: after an ambiguous failure the history is checked once
+// more and, when the transfer is not there yet, a *TransferStatusError is
+// returned. Call Transfer again later with the same Reference to settle it:
+// until the transfer shows up in the history or TransferSettlePeriod passes,
+// the client answers *TransferStatusError (ErrTransferUnsettled) without
+// sending it again, and concurrent calls with the same Reference get
+// *TransferStatusError (ErrTransferInProgress). Without a Reference ambiguous
+// failures are returned as a *TransferStatusError too. Only transfers sent by
+// the user in the last TransferLookupWindow are matched; when the history does
+// not tell sent from received transfers, set the Email of the client.
+func (t *TauAPI) Transfer(transfer TransferMsg) (TransferResult, error) {
+	return t.TransferContext(context.Background(), transfer)
+}
+
+// TransferContext - Transfer honouring the cancellation and deadline of ctx
+func (t *TauAPI) TransferContext(ctx context.Context, transfer TransferMsg) (res TransferResult, err error) {
+	if transfer.Reference != "" {
+		if !t.claimTransfer(transfer.Reference) {
+			return TransferResult{}, fmt.Errorf("Transfer->%w", &TransferStatusError{Reference: transfer.Reference, Err: ErrTransferInProgress})
+		}
+		defer func() { t.releaseTransfer(transfer.Reference, err) }()
+		res, err := t.lookupTransfer(ctx, transfer)
+		if err == nil {
+			res.Duplicate = true
+			return res, nil
+		}
+		if !errors.Is(err, ErrTransferNotFound) {
+			return TransferResult{}, fmt.Errorf("Transfer->%w", err)
+		}
+		if t.transferUnsettled(transfer.Reference) {
+			return TransferResult{}, fmt.Errorf("Transfer->%w", &TransferStatusError{Reference: transfer.Reference, Err: ErrTransferUnsettled})
+		}
+	}
+	release, err := t.checkPolicy(ctx, Outgoing{
+		Kind:        OutgoingTransfer,
+		Coin:        transfer.Coin,
+		Amount:      transfer.Amount,
+		Destination: transfer.Recipient,
+	})
+	if err != nil {
+		return TransferResult{}, fmt.Errorf("Transfer->%w", err)
+	}
+	res, err = t.submitTransfer(ctx, transfer)
+	if err != nil && transferAmbiguous(err) && transfer.Reference != "" {
+		//the funds may have moved: look for the transfer, but never send it again
+		if sleepContext(ctx, t.retry().backoff(1, 0)) == nil {
+			if found, lookupErr := t.lookupTransfer(ctx, transfer); lookupErr == nil {
+				res, err = found, nil
+			}
+		}
+	}
+	release(err)
+	if err != nil && transferAmbiguous(err) {
+		err = &TransferStatusError{Reference: transfer.Reference, Err: err}
+	}
+	if err != nil {
+		return TransferResult{}, fmt.Errorf("Transfer->%w", err)
+	}
+	res.Reference = transfer.Reference
+	return res, nil
+}
+
+// claimTransfer marks reference in flight, false when another call already holds it
+func (t *TauAPI) claimTransfer(reference string) bool {
+	t.transferMu.Lock()
+	defer t.transferMu.Unlock()
+	if t.transfersInFlight[reference] {
+		return false
+	}
+	if t.transfersInFlight == nil {
+		t.transfersInFlight = make(map[string]bool)
+	}
+	t.transfersInFlight[reference] = true
+	return true
+}
+
+// releaseTransfer ends the call holding reference with err: success settles
+// it, an unknown status keeps it from being sent again before
+// TransferSettlePeriod and other errors leave it as it was
+func (t *TauAPI) releaseTransfer(reference string, err error) {
+	t.transferMu.Lock()
+	defer t.transferMu.Unlock()
+	delete(t.transfersInFlight, reference)
+	if err == nil {
+		delete(t.transfersUnsettled, reference)
+		return
+	}
+	if !errors.Is(err, ErrTransferStatusUnknown) {
+		return
+	}
+	if _, ok := t.transfersUnsettled[reference]; ok {
+		return //keep the deadline of the transfer actually sent
+	}
+	if t.transfersUnsettled == nil {
+		t.transfersUnsettled = make(map[string]time.Time)
+	}
+	t.transfersUnsettled[reference] = time.Now().Add(TransferSettlePeriod)
+}
+
+// transferUnsettled reports whether a transfer with reference failed
+// ambiguously less than TransferSettlePeriod ago
+func (t *TauAPI) transferUnsettled(reference string) bool {
+	t.transferMu.Lock()
+	defer t.transferMu.Unlock()
+	deadline, ok := t.transfersUnsettled[reference]
+	if ok && time.Now().After(deadline) {
+		delete(t.transfersUnsettled, reference)
+		return false
+	}
+	return ok
+}
+
+// submitTransfer sends the transfer once, doTauRequest only retrying it when it was never sent
+func (t *TauAPI) submitTransfer(ctx context.Context, transfer TransferMsg) (TransferResult, error) {
+	var res TransferResult
+	jsonPostMsg, _ := json.Marshal(&transfer)
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
+		Version:   2,
+		Method:    "POST",
+		Path:      "wallets/inner-transfer",
+		NeedsAuth: true,
+		PostMsg:   jsonPostMsg,
+	})
+	if err != nil {
+		return res, err
+	}
+	if len(jsonData) > 0 && jsonData[0] == '{' {
+		if err := json.Unmarshal(jsonData, &res); err != nil {
+			return res, fmt.Errorf("unmarshal jsonData %w", err)
+		}
+	}
+	if res.Status == "" {
+		res.Status = TransferCompleted
+	}
+	return res, nil
+}
+
+// transferAmbiguous reports whether a failed transfer request may have moved the funds
+func transferAmbiguous(err error) bool {
+	var notSent *notSentError
+	if errors.As(err, &notSent) {
+		return false
+	}
+	var apiErr *APIError
+	return !errors.As(err, &apiErr) || apiErr.Temporary()
+}
+
+// LookupTransfer - find the transfer made with reference in the transfer history,
+// ErrTransferNotFound when there is none
+func (t *TauAPI) LookupTransfer(reference string) (TransferResult, error) {
+	return t.LookupTransferContext(context.Background(), reference)
+}
+
+// LookupTransferContext - LookupTransfer honouring the cancellation and deadline of ctx
+func (t *TauAPI) LookupTransferContext(ctx context.Context, reference string) (TransferResult, error) {
+	return t.lookupTransfer(ctx, TransferMsg{Reference: reference})
+}
+
+// TransferLookupWindow - how far back Transfer and LookupTransfer search the
+// transfer history for a reference; a reference reused after that is not detected
+const TransferLookupWindow = 30 * 24 * time.Hour
+
+// transferLookupPageSize - records requested per page of the transfer history
+const transferLookupPageSize = 100
+
+// lookupTransfer pages through the transfers sent in the last TransferLookupWindow
+// looking for transfer.Reference; when the transfer has a coin, recipient or
+// amount the record found must have the same ones
+func (t *TauAPI) lookupTransfer(ctx context.Context, transfer TransferMsg) (TransferResult, error) {
+	if transfer.Reference == "" {
+		return TransferResult{}, errors.New("LookupTransfer-> empty reference")
+	}
+	filter := WalletFilter{From: time.Now().Add(-TransferLookupWindow), Limit: transferLookupPageSize}
+	seen := make(map[int64]bool)
+	for ; ; filter.Offset += transferLookupPageSize {
+		var page []InnerTransfer
+		err := t.walletHistory(ctx, &TauReq{
+			Version:   2,
+			Method:    "GET",
+			Path:      "wallets/inner-transfer",
+			Query:     filter.query(""),
+			NeedsAuth: true,
+		}, &page)
+		if err != nil {
+			return TransferResult{}, fmt.Errorf("LookupTransfer-> %w", err)
+		}
+		fresh, recent := 0, 0
+		for _, tr := range page {
+			if seen[tr.ID] {
+				continue
+			}
+			seen[tr.ID] = true
+			fresh++
+			if filter.match(tr.DateTime) || tr.DateTime.IsZero() {
+				recent++
+			}
+			if tr.Description != transfer.Reference {
+				continue
+			}
+			outgoing, known := t.outgoingTransfer(tr)
+			if !known {
+				return TransferResult{}, fmt.Errorf("LookupTransfer-> transfer %d with reference %q may be incoming or outgoing, set TauAPI.Email to tell", tr.ID, transfer.Reference)
+			}
+			if outgoing {
+				return matchTransfer(transfer, tr)
+			}
+		}
+		//stop on the last page, or when the server ignores the paging or the date range
+		if len(page) < transferLookupPageSize || fresh == 0 || recent == 0 {
+			break
+		}
+	}
+	return TransferResult{}, fmt.Errorf("LookupTransfer-> %q: %w", transfer.Reference, ErrTransferNotFound)
+}
+
+// outgoingTransfer reports whether tr was sent by the user, from its
+// transaction type or else from its sender being the Email of the client;
+// known is false when neither tells
+func (t *TauAPI) outgoingTransfer(tr InnerTransfer) (outgoing, known bool) {
+	switch strings.ToLower(tr.TransactionType) {
+	case "send", "sent", "outgoing", "debit":
+		return true, true
+	case "receive", "received", "incoming", "credit":
+		return false, true
+	}
+	if t.Email != "" && tr.Sender != "" {
+		return strings.EqualFold(tr.Sender, t.Email), true
+	}
+	return false, false
+}
+
+// matchTransfer checks that the outgoing record tr carrying the reference of
+// transfer is that transfer and not another one reusing the reference
+func matchTransfer(transfer TransferMsg, tr InnerTransfer) (TransferResult, error) {
+	if (transfer.Coin != "" && !strings.EqualFold(tr.Coin, transfer.Coin)) ||
+		(transfer.Recipient != "" && !strings.EqualFold(tr.Receiver, transfer.Recipient)) || //an empty receiver cannot be confirmed
+		(transfer.Amount.Sign() != 0 && !tr.Amount.Equal(transfer.Amount)) {
+		return TransferResult{}, fmt.Errorf("LookupTransfer-> reference %q already used by transfer %d of %s %s to %q",
+			transfer.Reference, tr.ID, tr.Amount, tr.Coin, tr.Receiver)
+	}
+	return TransferResult{ID: tr.ID, Status: TransferCompleted, Reference: tr.Description}, nil
+}
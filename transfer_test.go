@@ -0,0 +1,307 @@
+package taurosapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTransfers records the inner transfers it executes and serves them as history;
+// tests change and read it through its methods as the server runs concurrently
+type fakeTransfers struct {
+	mu       sync.Mutex
+	history  []InnerTransfer
+	posts    int
+	slow     bool          // execute the transfer but answer too late
+	late     bool          // answer too late and only then execute the transfer
+	recorded chan struct{} // signalled when a late transfer reaches the history
+	failNext int           // answer 503 without executing the transfer
+}
+
+func (f *fakeTransfers) set(slow, late bool, failNext int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.slow, f.late, f.failNext = slow, late, failNext
+	if late {
+		f.recorded = make(chan struct{})
+	}
+}
+
+// counts returns the transfer requests received and the transfers executed
+func (f *fakeTransfers) counts() (posts, executed int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.posts, len(f.history)
+}
+
+// seed adds transfers made before the test to the history
+func (f *fakeTransfers) seed(history ...InnerTransfer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.history = append(f.history, history...)
+}
+
+func (f *fakeTransfers) record(msg TransferMsg) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := int64(100 + len(f.history))
+	f.history = append(f.history, InnerTransfer{ID: id, Coin: msg.Coin, Amount: msg.Amount, Receiver: msg.Recipient,
+		Description: msg.Reference, TransactionType: "send", DateTime: Timestamp{time.Now()}})
+	return id
+}
+
+func (f *fakeTransfers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	if r.Method == "GET" {
+		page := f.history
+		if offset, _ := strconv.Atoi(r.URL.Query().Get("offset")); offset < len(page) {
+			page = page[offset:]
+		} else {
+			page = nil
+		}
+		if limit, _ := strconv.Atoi(r.URL.Query().Get("limit")); limit > 0 && limit < len(page) {
+			page = page[:limit]
+		}
+		data, _ := json.Marshal(page)
+		f.mu.Unlock()
+		fmt.Fprintf(w, `{"success":true,"payload":%s}`, data)
+		return
+	}
+	f.posts++
+	if f.failNext > 0 {
+		f.failNext--
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	slow, late, recorded := f.slow, f.late, f.recorded
+	f.mu.Unlock()
+	var msg TransferMsg
+	body, _ := ioutil.ReadAll(r.Body)
+	json.Unmarshal(body, &msg)
+	if late {
+		time.Sleep(200 * time.Millisecond)
+		id := f.record(msg)
+		close(recorded)
+		fmt.Fprintf(w, `{"success":true,"payload":{"id":%d,"status":"completed"}}`, id)
+		return
+	}
+	id := f.record(msg)
+	if slow {
+		time.Sleep(200 * time.Millisecond)
+	}
+	fmt.Fprintf(w, `{"success":true,"payload":{"id":%d,"status":"completed"}}`, id)
+}
+
+func TestTransferIdempotent(t *testing.T) {
+	fake := &fakeTransfers{}
+	c, _ := newTestClient(t, fake, WithRetryPolicy(fastRetry), WithTimeout(50*time.Millisecond))
+	transfer := TransferMsg{Coin: "MXN", Recipient: "a@example.com", Amount: MustDecimal("10"), Reference: "payout-1"}
+
+	//executed but timed out: found in the history instead of being paid twice
+	fake.set(true, false, 0)
+	res, err := c.Transfer(transfer)
+	if err != nil || res.ID != 100 || res.Duplicate || res.Reference != "payout-1" {
+		t.Fatalf("unexpected result %+v, %v", res, err)
+	}
+	fake.set(false, false, 0)
+	res, err = c.Transfer(transfer)
+	if err != nil || res.ID != 100 || !res.Duplicate {
+		t.Errorf("retry was not detected as duplicate: %+v, %v", res, err)
+	}
+	if posts, _ := fake.counts(); posts != 1 {
+		t.Errorf("transfer sent %d times", posts)
+	}
+
+	//not executed: reported as unknown, not sent again before the settle period,
+	//sent by the retry after it once the history still does not have it
+	transfer.Reference = "payout-2"
+	fake.set(false, false, 1)
+	if _, err = c.Transfer(transfer); !errors.Is(err, ErrTransferStatusUnknown) {
+		t.Fatalf("expected unknown status, got %v", err)
+	}
+	if _, err = c.Transfer(transfer); !errors.Is(err, ErrTransferUnsettled) || !errors.Is(err, ErrTransferStatusUnknown) {
+		t.Fatalf("expected unsettled transfer, got %v", err)
+	}
+	expireTransfer(c, "payout-2")
+	res, err = c.Transfer(transfer)
+	if err != nil || res.ID != 101 || res.Duplicate || res.Status != TransferCompleted {
+		t.Errorf("unexpected result %+v, %v", res, err)
+	}
+	if posts, executed := fake.counts(); posts != 3 || executed != 2 {
+		t.Errorf("%d posts for %d transfers", posts, executed)
+	}
+
+	res, err = c.LookupTransfer("payout-2")
+	if err != nil || res.ID != 101 {
+		t.Errorf("LookupTransfer: %+v, %v", res, err)
+	}
+	if _, err = c.LookupTransfer("payout-3"); !errors.Is(err, ErrTransferNotFound) {
+		t.Errorf("expected ErrTransferNotFound, got %v", err)
+	}
+	transfer.Amount = MustDecimal("11")
+	if _, err = c.Transfer(transfer); err == nil {
+		t.Error("reused reference with another amount")
+	}
+	if posts, _ := fake.counts(); posts != 3 {
+		t.Errorf("transfer with a reused reference was sent")
+	}
+}
+
+// expireTransfer ends the settle period of reference
+func expireTransfer(c *TauAPI, reference string) {
+	c.transferMu.Lock()
+	defer c.transferMu.Unlock()
+	c.transfersUnsettled[reference] = time.Now().Add(-time.Second)
+}
+
+func TestTransferConcurrentReference(t *testing.T) {
+	fake := &fakeTransfers{}
+	fake.set(true, false, 0)
+	c, _ := newTestClient(t, fake)
+	transfer := TransferMsg{Coin: "MXN", Recipient: "a@example.com", Amount: MustDecimal("10"), Reference: "payout-1"}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = c.Transfer(transfer)
+		}(i)
+	}
+	wg.Wait()
+	if posts, _ := fake.counts(); posts != 1 {
+		t.Errorf("transfer with one reference sent %d times", posts)
+	}
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, ErrTransferInProgress) {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+}
+
+func TestTransferUnsettled(t *testing.T) {
+	fake := &fakeTransfers{}
+	c, _ := newTestClient(t, fake, WithRetryPolicy(fastRetry), WithTimeout(50*time.Millisecond))
+	transfer := TransferMsg{Coin: "MXN", Recipient: "a@example.com", Amount: MustDecimal("10"), Reference: "payout-1"}
+
+	//answered too late, history not updated when the client retries
+	fake.set(false, true, 0)
+	if _, err := c.Transfer(transfer); !errors.Is(err, ErrTransferStatusUnknown) {
+		t.Fatalf("expected unknown status, got %v", err)
+	}
+	<-fake.recorded
+	fake.set(false, false, 0)
+	fake.mu.Lock()
+	fake.history = nil //lagging behind
+	fake.mu.Unlock()
+	for i := 0; i < 3; i++ {
+		if _, err := c.Transfer(transfer); !errors.Is(err, ErrTransferUnsettled) {
+			t.Fatalf("expected unsettled transfer, got %v", err)
+		}
+	}
+	if posts, _ := fake.counts(); posts != 1 {
+		t.Errorf("unsettled transfer sent %d times", posts)
+	}
+
+	expireTransfer(c, "payout-1")
+	if res, err := c.Transfer(transfer); err != nil || res.Duplicate {
+		t.Errorf("transfer not sent after the settle period: %+v, %v", res, err)
+	}
+	if posts, _ := fake.counts(); posts != 2 {
+		t.Errorf("%d posts, want 2", posts)
+	}
+}
+
+func TestTransferExecutedAfterAnswer(t *testing.T) {
+	fake := &fakeTransfers{}
+	c, _ := newTestClient(t, fake, WithRetryPolicy(fastRetry), WithTimeout(50*time.Millisecond))
+	transfer := TransferMsg{Coin: "MXN", Recipient: "a@example.com", Amount: MustDecimal("10"), Reference: "payout-1"}
+
+	//the history shows the transfer only after the client gave up: never resubmitted
+	fake.set(false, true, 0)
+	var statusErr *TransferStatusError
+	if _, err := c.Transfer(transfer); !errors.As(err, &statusErr) || statusErr.Reference != "payout-1" {
+		t.Fatalf("expected TransferStatusError, got %v", err)
+	}
+	<-fake.recorded
+	fake.set(false, false, 0)
+	res, err := c.Transfer(transfer)
+	if err != nil || !res.Duplicate || res.ID != 100 {
+		t.Errorf("retry was not detected as duplicate: %+v, %v", res, err)
+	}
+	if posts, executed := fake.counts(); posts != 1 || executed != 1 {
+		t.Errorf("%d posts for %d transfers", posts, executed)
+	}
+}
+
+func TestLookupTransferHistory(t *testing.T) {
+	fake := &fakeTransfers{}
+	now := Timestamp{time.Now()}
+	for i := 0; i < 2*transferLookupPageSize; i++ {
+		fake.seed(InnerTransfer{ID: int64(1000 + i), Coin: "MXN", Amount: MustDecimal("1"), Receiver: "b@example.com", TransactionType: "send", DateTime: now})
+	}
+	fake.seed(
+		InnerTransfer{ID: 1, Coin: "MXN", Amount: MustDecimal("10"), Sender: "a@example.com", Receiver: "me@example.com", Description: "payout-1", DateTime: now},
+		InnerTransfer{ID: 2, Coin: "MXN", Amount: MustDecimal("10"), Sender: "me@example.com", Receiver: "a@example.com", Description: "payout-2", DateTime: now},
+		InnerTransfer{ID: 3, Coin: "MXN", Amount: MustDecimal("10"), Sender: "me@example.com", Description: "payout-3", DateTime: now},
+		InnerTransfer{ID: 4, Coin: "MXN", Amount: MustDecimal("10"), Receiver: "a@example.com", Description: "payout-4", TransactionType: "receive", DateTime: now},
+	)
+	c, _ := newTestClient(t, fake, WithRateLimit(nil, nil))
+	transfer := TransferMsg{Coin: "MXN", Recipient: "a@example.com", Amount: MustDecimal("10")}
+
+	transfer.Reference = "payout-2"
+	if _, err := c.Transfer(transfer); err == nil {
+		t.Error("expected error: the direction of transfers without type is unknown without Email")
+	}
+	c.Email = "me@example.com"
+	res, err := c.Transfer(transfer) //found on the third page
+	if err != nil || !res.Duplicate || res.ID != 2 {
+		t.Errorf("payout-2: %+v, %v", res, err)
+	}
+	transfer.Reference = "payout-3" //sent, but the receiver is not in the record
+	if res, err = c.Transfer(transfer); err == nil || res.Duplicate {
+		t.Errorf("payout-3 without receiver: %+v, %v", res, err)
+	}
+	if posts, _ := fake.counts(); posts != 0 {
+		t.Errorf("%d transfers sent", posts)
+	}
+	for _, ref := range []string{"payout-1", "payout-4"} { //received, not sent
+		transfer.Reference = ref
+		if res, err = c.Transfer(transfer); err != nil || res.Duplicate {
+			t.Errorf("%s: incoming transfer taken for a duplicate: %+v, %v", ref, res, err)
+		}
+	}
+	if posts, _ := fake.counts(); posts != 2 {
+		t.Errorf("%d transfers sent, want 2", posts)
+	}
+}
+
+func TestTransferStatusUnknown(t *testing.T) {
+	fake := &fakeTransfers{}
+	fake.set(true, false, 0)
+	c, _ := newTestClient(t, fake, WithRetryPolicy(fastRetry), WithTimeout(50*time.Millisecond))
+	_, err := c.Transfer(TransferMsg{Coin: "MXN", Recipient: "a@example.com", Amount: MustDecimal("10")})
+	var statusErr *TransferStatusError
+	if !errors.As(err, &statusErr) || !errors.Is(err, ErrTransferStatusUnknown) {
+		t.Errorf("expected TransferStatusError, got %v", err)
+	}
+	if posts, _ := fake.counts(); posts != 1 {
+		t.Errorf("transfer without reference sent %d times", posts)
+	}
+}
+
+func TestTransferNotSent(t *testing.T) {
+	c, _ := NewClient("key", "c2VjcmV0", WithBaseURL("http://127.0.0.1:1"), WithRetryPolicy(NoRetry))
+	_, err := c.Transfer(TransferMsg{Coin: "MXN", Recipient: "a@example.com", Amount: MustDecimal("10")})
+	if err == nil || errors.Is(err, ErrTransferStatusUnknown) {
+		t.Errorf("refused connection reported as unknown status: %v", err)
+	}
+}
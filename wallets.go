@@ -0,0 +1,212 @@
+package taurosapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Deposit - funds deposited into a wallet of the user
+type Deposit struct {
+	ID              int64     `json:"id"`
+	Coin            string    `json:"coin"`
+	CoinName        string    `json:"coin_name"`
+	Amount          Decimal   `json:"amount"`
+	Address         string    `json:"address"`
+	TxID            string    `json:"txId"` //todo: github issue correcting json format to "tx_id"
+	ExplorerLink    string    `json:"explorer_link"`
+	Confirmed       bool      `json:"confirmed"`
+	ConfirmedAt     Timestamp `json:"confirmed_at"`
+	IsInnerTransfer bool      `json:"is_innerTransfer"` //todo: issue to correct json name to is_inner_transfer
+	Sender          string    `json:"sender"`
+	CreatedAt       Timestamp `json:"created_at"`
+}
+
+// Withdrawal - funds withdrawn from a wallet of the user
+type Withdrawal struct {
+	ID              int64     `json:"id"`
+	Coin            string    `json:"coin"`
+	CoinName        string    `json:"coin_name"`
+	Amount          Decimal   `json:"amount"`
+	FeeAmount       Decimal   `json:"fee_amount"`
+	TotalAmount     Decimal   `json:"total_amount"`
+	Address         string    `json:"address"`
+	TxID            string    `json:"txId"`
+	ExplorerLink    string    `json:"explorer_link"`
+	Confirmed       bool      `json:"confirmed"`
+	ConfirmedAt     Timestamp `json:"confirmed_at"`
+	IsInnerTransfer bool      `json:"is_innerTransfer"`
+	Receiver        string    `json:"receiver"`
+	CreatedAt       Timestamp `json:"created_at"`
+}
+
+// InnerTransfer - funds moved between Tauros accounts with Transfer
+type InnerTransfer struct {
+	ID              int64     `json:"id"`
+	Coin            string    `json:"coin"`
+	Amount          Decimal   `json:"amount"`
+	Sender          string    `json:"sender"`
+	Receiver        string    `json:"receiver"`
+	Description     string    `json:"description"`
+	TransactionType string    `json:"transaction_type"`
+	DateTime        Timestamp `json:"datetime"`
+}
+
+// WalletFilter - criteria of the wallet history endpoints, zero fields do not filter
+type WalletFilter struct {
+	From   time.Time // created at or after
+	To     time.Time // created before
+	Limit  int       // maximum number of records returned, 0 for the server default
+	Offset int       // records to skip, for paging
+}
+
+func (f WalletFilter) query(coin string) url.Values {
+	q := url.Values{}
+	if coin != "" {
+		q.Set("coin", strings.ToLower(coin))
+	}
+	if !f.From.IsZero() {
+		q.Set("start_date", f.From.UTC().Format(time.RFC3339))
+	}
+	if !f.To.IsZero() {
+		q.Set("end_date", f.To.UTC().Format(time.RFC3339))
+	}
+	if f.Limit > 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	if f.Offset > 0 {
+		q.Set("offset", strconv.Itoa(f.Offset))
+	}
+	return q
+}
+
+func (f WalletFilter) validate() error {
+	if !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return fmt.Errorf("wallet filter ends (%s) before it starts (%s)", f.To, f.From)
+	}
+	return nil
+}
+
+// match reports whether a record created at ts is in the date range of the filter
+func (f WalletFilter) match(ts Timestamp) bool {
+	return (f.From.IsZero() || !ts.Before(f.From)) && (f.To.IsZero() || ts.Before(f.To))
+}
+
+// walletHistory requests one of the history endpoints and decodes its records
+// into out, which the endpoint sends either as a list or as a page with "results"
+func (t *TauAPI) walletHistory(ctx context.Context, tauReq *TauReq, out interface{}) error {
+	jsonData, err := t.doTauRequest(ctx, tauReq)
+	if err != nil {
+		return err
+	}
+	if len(jsonData) > 0 && jsonData[0] != '[' {
+		var page struct {
+			Results json.RawMessage `json:"results"`
+		}
+		if err := json.Unmarshal(jsonData, &page); err != nil {
+			return fmt.Errorf("unmarshal jsonData %w", err)
+		}
+		jsonData = page.Results
+	}
+	if len(jsonData) == 0 || string(jsonData) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(jsonData, out); err != nil {
+		return fmt.Errorf("unmarshal jsonData %w", err)
+	}
+	return nil
+}
+
+// GetDeposits - get the deposits of the user in coin, every coin when coin is empty
+func (t *TauAPI) GetDeposits(coin string, filter WalletFilter) ([]Deposit, error) {
+	return t.GetDepositsContext(context.Background(), coin, filter)
+}
+
+// GetDepositsContext - GetDeposits honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetDepositsContext(ctx context.Context, coin string, filter WalletFilter) ([]Deposit, error) {
+	if err := filter.validate(); err != nil {
+		return nil, fmt.Errorf("GetDeposits-> %w", err)
+	}
+	var all []Deposit
+	err := t.walletHistory(ctx, &TauReq{
+		Version:   1,
+		Method:    "GET",
+		Path:      "data/listdeposits",
+		Query:     filter.query(coin),
+		NeedsAuth: true,
+	}, &all)
+	if err != nil {
+		return nil, fmt.Errorf("GetDeposits-> %w", err)
+	}
+	deposits := all[:0]
+	for _, d := range all {
+		if filter.match(d.CreatedAt) && (coin == "" || strings.EqualFold(d.Coin, coin)) {
+			deposits = append(deposits, d)
+		}
+	}
+	return deposits, nil
+}
+
+// GetWithdrawals - get the withdrawals of the user in coin, every coin when coin is empty
+func (t *TauAPI) GetWithdrawals(coin string, filter WalletFilter) ([]Withdrawal, error) {
+	return t.GetWithdrawalsContext(context.Background(), coin, filter)
+}
+
+// GetWithdrawalsContext - GetWithdrawals honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetWithdrawalsContext(ctx context.Context, coin string, filter WalletFilter) ([]Withdrawal, error) {
+	if err := filter.validate(); err != nil {
+		return nil, fmt.Errorf("GetWithdrawals-> %w", err)
+	}
+	var all []Withdrawal
+	err := t.walletHistory(ctx, &TauReq{
+		Version:   1,
+		Method:    "GET",
+		Path:      "data/listwithdrawals",
+		Query:     filter.query(coin),
+		NeedsAuth: true,
+	}, &all)
+	if err != nil {
+		return nil, fmt.Errorf("GetWithdrawals-> %w", err)
+	}
+	withdrawals := all[:0]
+	for _, w := range all {
+		if filter.match(w.CreatedAt) && (coin == "" || strings.EqualFold(w.Coin, coin)) {
+			withdrawals = append(withdrawals, w)
+		}
+	}
+	return withdrawals, nil
+}
+
+// GetInnerTransfers - get the transfers between Tauros accounts sent or received by the user
+func (t *TauAPI) GetInnerTransfers(filter WalletFilter) ([]InnerTransfer, error) {
+	return t.GetInnerTransfersContext(context.Background(), filter)
+}
+
+// GetInnerTransfersContext - GetInnerTransfers honouring the cancellation and deadline of ctx
+func (t *TauAPI) GetInnerTransfersContext(ctx context.Context, filter WalletFilter) ([]InnerTransfer, error) {
+	if err := filter.validate(); err != nil {
+		return nil, fmt.Errorf("GetInnerTransfers-> %w", err)
+	}
+	var all []InnerTransfer
+	err := t.walletHistory(ctx, &TauReq{
+		Version:   2,
+		Method:    "GET",
+		Path:      "wallets/inner-transfer",
+		Query:     filter.query(""),
+		NeedsAuth: true,
+	}, &all)
+	if err != nil {
+		return nil, fmt.Errorf("GetInnerTransfers-> %w", err)
+	}
+	transfers := all[:0]
+	for _, tr := range all {
+		if filter.match(tr.DateTime) {
+			transfers = append(transfers, tr)
+		}
+	}
+	return transfers, nil
+}
@@ -0,0 +1,59 @@
+package taurosapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWalletHistory(t *testing.T) {
+	queries := map[string]string{}
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries[r.URL.Path] = r.URL.RawQuery
+		switch r.URL.Path {
+		case "/api/v1/data/listdeposits/":
+			w.Write([]byte(`{"success":true,"data":[
+				{"id":1,"coin":"BTC","amount":"0.5","txId":"abc","confirmed":true,"confirmed_at":"2020-01-02T10:00:00Z","explorer_link":"https://explorer/abc","created_at":"2020-01-02T09:00:00Z"},
+				{"id":2,"coin":"BTC","amount":"0.1","txId":"old","created_at":"2019-12-31T09:00:00Z"}]}`))
+		case "/api/v1/data/listwithdrawals/":
+			w.Write([]byte(`{"success":true,"data":{"count":1,"results":[
+				{"id":3,"coin":"BTC","amount":"0.2","fee_amount":"0.0005","total_amount":"0.2005","txId":"def","confirmed":false,"created_at":"2020-01-03T09:00:00Z"}]}}`))
+		case "/api/v2/wallets/inner-transfer/":
+			w.Write([]byte(`{"success":true,"payload":{"results":[{"id":4,"coin":"MXN","amount":"100","sender":"a@example.com","receiver":"b@example.com","datetime":"2020-01-04T09:00:00Z"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	filter := WalletFilter{From: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	deposits, err := c.GetDeposits("BTC", filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != 1 || deposits[0].TxID != "abc" || !deposits[0].Confirmed || deposits[0].ConfirmedAt.IsZero() {
+		t.Errorf("unexpected deposits %+v", deposits)
+	}
+	if q := queries["/api/v1/data/listdeposits/"]; q != "coin=btc&start_date=2020-01-01T00%3A00%3A00Z" {
+		t.Errorf("unexpected deposits query %s", q)
+	}
+
+	withdrawals, err := c.GetWithdrawals("", WalletFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(withdrawals) != 1 || withdrawals[0].Confirmed || !withdrawals[0].FeeAmount.Equal(MustDecimal("0.0005")) {
+		t.Errorf("unexpected withdrawals %+v", withdrawals)
+	}
+
+	transfers, err := c.GetInnerTransfers(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transfers) != 1 || transfers[0].Receiver != "b@example.com" {
+		t.Errorf("unexpected transfers %+v", transfers)
+	}
+
+	if _, err := c.GetDeposits("BTC", WalletFilter{From: filter.From, To: filter.From.Add(-time.Hour)}); err == nil {
+		t.Error("expected error for inverted date range")
+	}
+}
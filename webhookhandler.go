@@ -0,0 +1,227 @@
+package taurosapi
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultWebhookMaxBodySize - largest webhook body accepted by default
+const DefaultWebhookMaxBodySize = 1 << 20
+
+// WebhookDelivery - one webhook POST received from Tauros
+type WebhookDelivery struct {
+	Request *http.Request
+	Event   Event
+	Payload EventPayload // Event.Object decoded according to Event.Type
+
+	// Duplicate is true when the handler Store already recorded this event as
+	// processed: Tauros delivered it again and it must not be applied twice.
+	// Only set when the handler has DeliverDuplicates.
+	Duplicate bool
+}
+
+// WebhookFunc - callback for a webhook delivery; returning an error answers
+// 500 so that Tauros delivers the message again
+type WebhookFunc func(d *WebhookDelivery) error
+
+// WebhookStats - outcome of the requests served by a WebhookHandler
+type WebhookStats struct {
+	Accepted int64 // messages handled successfully
+	Rejected int64 // requests refused: wrong method or authorization, too large or undecodable
+	Failed   int64 // messages whose callbacks returned an error
+
+	Duplicates int64 // accepted messages that had already been processed
+}
+
+// WebhookHandler - http.Handler receiving the webhook POSTs of Tauros
+//
+// Requests must carry the AuthorizationHeader/AuthorizationContent pair
+// configured on the Webhook, which is checked in constant time. The decoded
+// message is dispatched to the callbacks registered for its event type.
+//
+// With a Store, every event is recorded once all its callbacks succeeded and
+// redeliveries are answered 200 without reaching the callbacks, or reach them
+// flagged as Duplicate with DeliverDuplicates. A redelivery arriving while the
+// first delivery is still being handled is answered 409 so Tauros tries again
+// later.
+type WebhookHandler struct {
+	MaxBodySize int64      // default DefaultWebhookMaxBodySize
+	Store       EventStore // deduplication of redelivered events, nil disables it
+
+	DeliverDuplicates bool // pass redeliveries to the callbacks flagged as Duplicate instead of dropping them
+
+	authHeader      string
+	authContent     string
+	unauthenticated bool // requests are accepted without authorization, see NewUnauthenticatedWebhookHandler
+
+	mu       sync.RWMutex
+	handlers map[string][]WebhookFunc
+	any      []WebhookFunc
+
+	inflightMu sync.Mutex
+	inflight   map[string]bool
+
+	accepted, rejected, failed, duplicates int64
+}
+
+// NewWebhookHandler - handler for the deliveries of webhook, which must have
+// an AuthorizationHeader and AuthorizationContent
+func NewWebhookHandler(webhook Webhook) (*WebhookHandler, error) {
+	if webhook.AuthorizationHeader == "" || webhook.AuthorizationContent == "" {
+		return nil, errors.New("NewWebhookHandler-> webhook has no AuthorizationHeader and AuthorizationContent, anyone could post to it")
+	}
+	h := newWebhookHandler()
+	h.authHeader = webhook.AuthorizationHeader
+	h.authContent = webhook.AuthorizationContent
+	return h, nil
+}
+
+// NewUnauthenticatedWebhookHandler - handler accepting every request, for
+// endpoints already authenticated by other means such as a private network
+func NewUnauthenticatedWebhookHandler() *WebhookHandler {
+	h := newWebhookHandler()
+	h.unauthenticated = true
+	return h
+}
+
+func newWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{
+		MaxBodySize: DefaultWebhookMaxBodySize,
+		handlers:    make(map[string][]WebhookFunc),
+		inflight:    make(map[string]bool),
+	}
+}
+
+// On - call fn for every message of the given event type (EventDeposit, EventTrade, ...)
+func (h *WebhookHandler) On(eventType string, fn WebhookFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[eventType] = append(h.handlers[eventType], fn)
+}
+
+// OnAny - call fn for every message
+func (h *WebhookHandler) OnAny(fn WebhookFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.any = append(h.any, fn)
+}
+
+// Stats - counts of the requests served so far
+func (h *WebhookHandler) Stats() WebhookStats {
+	return WebhookStats{
+		Accepted: atomic.LoadInt64(&h.accepted),
+		Rejected: atomic.LoadInt64(&h.rejected),
+		Failed:   atomic.LoadInt64(&h.failed),
+
+		Duplicates: atomic.LoadInt64(&h.duplicates),
+	}
+}
+
+func (h *WebhookHandler) reject(w http.ResponseWriter, status int) {
+	atomic.AddInt64(&h.rejected, 1)
+	http.Error(w, http.StatusText(status), status)
+}
+
+// ServeHTTP - authorize, decode and dispatch one delivery
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.reject(w, http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.unauthenticated {
+		got := r.Header.Get(h.authHeader)
+		if h.authHeader == "" || subtle.ConstantTimeCompare([]byte(got), []byte(h.authContent)) != 1 {
+			h.reject(w, http.StatusUnauthorized)
+			return
+		}
+	}
+	maxBody := h.MaxBodySize
+	if maxBody <= 0 {
+		maxBody = DefaultWebhookMaxBodySize
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+	if err != nil {
+		if strings.Contains(err.Error(), "too large") { //*http.MaxBytesError needs go1.19
+			h.reject(w, http.StatusRequestEntityTooLarge)
+		} else {
+			h.reject(w, http.StatusBadRequest)
+		}
+		return
+	}
+	d := &WebhookDelivery{Request: r}
+	if err := json.Unmarshal(body, &d.Event); err != nil {
+		h.reject(w, http.StatusBadRequest)
+		return
+	}
+	if d.Payload, err = d.Event.Payload(); err != nil {
+		h.reject(w, http.StatusBadRequest)
+		return
+	}
+	key := d.Event.Key()
+	if h.Store != nil {
+		if !h.begin(key) {
+			h.reject(w, http.StatusConflict)
+			return
+		}
+		defer h.end(key)
+		if d.Duplicate, err = h.Store.Seen(key); err != nil {
+			h.fail(w)
+			return
+		}
+		if d.Duplicate && !h.DeliverDuplicates {
+			atomic.AddInt64(&h.duplicates, 1)
+			atomic.AddInt64(&h.accepted, 1)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+	h.mu.RLock()
+	handlers := append(append([]WebhookFunc{}, h.handlers[d.Event.Type]...), h.any...)
+	h.mu.RUnlock()
+	for _, fn := range handlers {
+		if err := fn(d); err != nil {
+			h.fail(w)
+			return
+		}
+	}
+	if h.Store != nil && !d.Duplicate {
+		if err := h.Store.MarkProcessed(key); err != nil {
+			h.fail(w)
+			return
+		}
+	}
+	if d.Duplicate {
+		atomic.AddInt64(&h.duplicates, 1)
+	}
+	atomic.AddInt64(&h.accepted, 1)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) fail(w http.ResponseWriter) {
+	atomic.AddInt64(&h.failed, 1)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// begin marks key as being handled, false if another delivery of it is in progress
+func (h *WebhookHandler) begin(key string) bool {
+	h.inflightMu.Lock()
+	defer h.inflightMu.Unlock()
+	if h.inflight[key] {
+		return false
+	}
+	h.inflight[key] = true
+	return true
+}
+
+func (h *WebhookHandler) end(key string) {
+	h.inflightMu.Lock()
+	defer h.inflightMu.Unlock()
+	delete(h.inflight, key)
+}
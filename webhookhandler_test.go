@@ -0,0 +1,143 @@
+package taurosapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	h, err := NewWebhookHandler(Webhook{AuthorizationHeader: "X-Auth", AuthorizationContent: "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.MaxBodySize = 512
+	var deposits, any int
+	h.On(EventDeposit, func(d *WebhookDelivery) error {
+		deposits++
+		if dep, ok := d.Payload.(*DepositEvent); !ok || dep.Coin != "BTC" || dep.Amount.String() != "0.5" || dep.TxID != "abc" {
+			t.Errorf("unexpected deposit %+v", d.Payload)
+		}
+		return nil
+	})
+	h.OnAny(func(d *WebhookDelivery) error {
+		any++
+		if d.Event.Type == EventTrade {
+			return errors.New("db down")
+		}
+		return nil
+	})
+	deposit := `{"type":"deposit","object":{"coin":"BTC","amount":"0.5","txId":"abc"}}`
+	tests := []struct {
+		method, auth, body string
+		status             int
+	}{
+		{"POST", "s3cret", deposit, http.StatusOK},
+		{"GET", "s3cret", deposit, http.StatusMethodNotAllowed},
+		{"POST", "wrong", deposit, http.StatusUnauthorized},
+		{"POST", "", deposit, http.StatusUnauthorized},
+		{"POST", "s3cret", "{", http.StatusBadRequest},
+		{"POST", "s3cret", `{"type":"deposit","description":"` + strings.Repeat("x", 1024) + `"}`, http.StatusRequestEntityTooLarge},
+		{"POST", "s3cret", `{"type":"trade"}`, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/hook", strings.NewReader(tt.body))
+		if tt.auth != "" {
+			req.Header.Set("X-Auth", tt.auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s %s %.20s: got %d, want %d", tt.method, tt.auth, tt.body, rec.Code, tt.status)
+		}
+	}
+	if deposits != 1 || any != 2 {
+		t.Errorf("unexpected dispatch: %d deposits, %d any", deposits, any)
+	}
+	if s := h.Stats(); s.Accepted != 1 || s.Rejected != 5 || s.Failed != 1 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestWebhookHandlerNeedsAuthorization(t *testing.T) {
+	for _, w := range []Webhook{{}, {AuthorizationHeader: "X-Auth"}, {AuthorizationContent: "s3cret"}} {
+		if _, err := NewWebhookHandler(w); err == nil {
+			t.Errorf("%+v: expected error", w)
+		}
+	}
+	h := &WebhookHandler{} //not built by a constructor
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/hook", strings.NewReader(`{"type":"deposit"}`)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("zero handler answered %d", rec.Code)
+	}
+}
+
+func TestWebhookHandlerDeduplicates(t *testing.T) {
+	h := NewUnauthenticatedWebhookHandler()
+	h.Store = NewMemoryEventStore(10)
+	credited := 0
+	fail := true
+	h.On(EventDeposit, func(d *WebhookDelivery) error {
+		if fail {
+			fail = false
+			return errors.New("ledger unavailable")
+		}
+		credited++
+		return nil
+	})
+	deposit := `{"type":"deposit","date":"2020-01-02T03:04:05Z","object":{"id":7,"txId":"abc","amount":"1"}}`
+	want := []int{http.StatusInternalServerError, http.StatusOK, http.StatusOK}
+	for i, status := range want {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", "/hook", strings.NewReader(deposit)))
+		if rec.Code != status {
+			t.Errorf("delivery %d: got %d, want %d", i, rec.Code, status)
+		}
+	}
+	if credited != 1 {
+		t.Errorf("deposit credited %d times", credited)
+	}
+	if s := h.Stats(); s.Accepted != 2 || s.Duplicates != 1 || s.Failed != 1 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+
+	h.DeliverDuplicates = true
+	var flagged bool
+	h.OnAny(func(d *WebhookDelivery) error {
+		flagged = d.Duplicate
+		return nil
+	})
+	redelivered := strings.Replace(deposit, "2020-01-02", "2020-01-03", 1) //stamped again
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/hook", strings.NewReader(redelivered)))
+	if !flagged || credited != 2 {
+		t.Errorf("redelivery not passed flagged: flagged %v, credited %d", flagged, credited)
+	}
+}
+
+func TestWebhookHandlerDeliversUpdates(t *testing.T) {
+	h := NewUnauthenticatedWebhookHandler()
+	h.Store = NewMemoryEventStore(10)
+	var got []string
+	h.OnAny(func(d *WebhookDelivery) error {
+		got = append(got, string(d.Event.Object))
+		return nil
+	})
+	for _, body := range []string{
+		`{"type":"order_filled","object":{"id":42,"filled":"0.5"}}`,
+		`{"type":"order_filled","object":{"id":42,"filled":"1.0"}}`,
+		`{"type":"order_filled","object":{"id":42,"filled":"1.0"}}`, //redelivery
+		`{"type":"deposit","object":{"id":7,"txId":"abc","confirmed":false}}`,
+		`{"type":"deposit","object":{"id":7,"txId":"abc","confirmed":true}}`,
+	} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/hook", strings.NewReader(body)))
+	}
+	if len(got) != 4 {
+		t.Errorf("expected both fills and both deposit updates, got %v", got)
+	}
+	if s := h.Stats(); s.Duplicates != 1 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
@@ -0,0 +1,189 @@
+package taurosapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MaxWebhooks - number of webhooks Tauros allows per account
+const MaxWebhooks = 5
+
+// WebhookAction - kind of change made by SyncWebhooks
+type WebhookAction string
+
+// Changes applied by SyncWebhooks
+const (
+	WebhookCreate WebhookAction = "create"
+	WebhookUpdate WebhookAction = "update"
+	WebhookDelete WebhookAction = "delete"
+)
+
+// WebhookChange - one step of a WebhookPlan
+type WebhookChange struct {
+	Action  WebhookAction
+	Current Webhook // registered webhook, zero for creations
+	Desired Webhook // wanted settings, zero for deletions
+	Done    bool    // the change was applied
+}
+
+// webhook is the webhook the change is about
+func (c WebhookChange) webhook() Webhook {
+	if c.Action == WebhookDelete {
+		return c.Current
+	}
+	return c.Desired
+}
+
+// WebhookPlan - what SyncWebhooks changed, or would change in dry-run mode
+type WebhookPlan struct {
+	DryRun    bool
+	Changes   []WebhookChange // creations before deletions, but for those needed to stay within MaxWebhooks
+	Unchanged []Webhook       // registered webhooks already matching the desired set
+}
+
+// Empty - whether the registered webhooks already match the desired set
+func (p WebhookPlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String - one line per change, e.g. "update MyWebhook https://example.com/hook (id 12)"
+func (p WebhookPlan) String() string {
+	var b strings.Builder
+	for _, c := range p.Changes {
+		w := c.webhook()
+		fmt.Fprintf(&b, "%s %s %s", c.Action, w.Name, w.Endpoint)
+		if c.Current.ID != 0 {
+			fmt.Fprintf(&b, " (id %d)", c.Current.ID)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// WebhookSyncOptions - how SyncWebhooks applies the desired webhooks
+type WebhookSyncOptions struct {
+	DryRun bool // only plan the changes
+
+	// SyncActive makes IsActive part of the wanted state. By default the
+	// IsActive of desired webhooks is ignored: registered webhooks keep theirs,
+	// e.g. one paused by hand stays paused, and new webhooks are created active.
+	SyncActive bool
+}
+
+// webhookKey identifies a webhook across deploys: the IDs change, name and endpoint do not
+func webhookKey(w Webhook) string {
+	return w.Name + "\x00" + w.Endpoint
+}
+
+// sameWebhookSettings compares the fields a deploy may change
+func sameWebhookSettings(a, b Webhook, syncActive bool) bool {
+	return a.NotifyDeposit == b.NotifyDeposit &&
+		a.NotifyWithdrawal == b.NotifyWithdrawal &&
+		a.NotifyOrderPlaced == b.NotifyOrderPlaced &&
+		a.NotifyOrderFilled == b.NotifyOrderFilled &&
+		a.NotifyTrade == b.NotifyTrade &&
+		a.AuthorizationHeader == b.AuthorizationHeader &&
+		a.AuthorizationContent == b.AuthorizationContent &&
+		(!syncActive || a.IsActive == b.IsActive)
+}
+
+// planWebhooks diffs the registered webhooks against the desired ones
+func planWebhooks(registered, desired []Webhook, opts WebhookSyncOptions) (WebhookPlan, error) {
+	plan := WebhookPlan{DryRun: opts.DryRun}
+	if len(desired) > MaxWebhooks {
+		return plan, fmt.Errorf("SyncWebhooks-> %d webhooks desired, limit is %d: %w", len(desired), MaxWebhooks, ErrWebhookLimit)
+	}
+	wanted := make(map[string]Webhook, len(desired))
+	for _, w := range desired {
+		k := webhookKey(w)
+		if _, dup := wanted[k]; dup {
+			return plan, fmt.Errorf("SyncWebhooks-> webhook %s %s desired twice", w.Name, w.Endpoint)
+		}
+		wanted[k] = w
+	}
+	var deletes, updates, creates []WebhookChange
+	matched := make(map[string]bool, len(registered))
+	for _, cur := range registered {
+		k := webhookKey(cur)
+		want, ok := wanted[k]
+		if !ok || matched[k] { //not wanted anymore, or a duplicate registration
+			deletes = append(deletes, WebhookChange{Action: WebhookDelete, Current: cur})
+			continue
+		}
+		matched[k] = true
+		if !opts.SyncActive {
+			want.IsActive = cur.IsActive
+		}
+		if sameWebhookSettings(cur, want, opts.SyncActive) {
+			plan.Unchanged = append(plan.Unchanged, cur)
+		} else {
+			updates = append(updates, WebhookChange{Action: WebhookUpdate, Current: cur, Desired: want})
+		}
+	}
+	for _, w := range desired {
+		if !matched[webhookKey(w)] {
+			if !opts.SyncActive {
+				w.IsActive = true
+			}
+			creates = append(creates, WebhookChange{Action: WebhookCreate, Desired: w})
+		}
+	}
+	//create before deleting so a moved webhook keeps delivering, deleting
+	//first only the webhooks that make room for the creations
+	room := len(registered) + len(creates) - MaxWebhooks
+	if room < 0 {
+		room = 0
+	}
+	plan.Changes = append(plan.Changes, deletes[:room]...)
+	plan.Changes = append(plan.Changes, creates...)
+	plan.Changes = append(plan.Changes, updates...)
+	plan.Changes = append(plan.Changes, deletes[room:]...)
+	return plan, nil
+}
+
+// SyncWebhooks - make the registered webhooks match desired
+//
+// Webhooks are matched by name and endpoint: registered webhooks missing from
+// desired are deleted, those whose settings differ are updated in place and
+// the remaining desired webhooks are created. The notify flags and
+// authorization of desired are the wanted state, IsActive only with
+// opts.SyncActive. Creations run before deletions, so a webhook whose endpoint
+// changed keeps delivering during the sync, except for the deletions needed
+// first to stay within MaxWebhooks; webhooks that did not change keep
+// delivering too.
+// With opts.DryRun nothing is changed and the plan tells what would be done.
+func (t *TauAPI) SyncWebhooks(desired []Webhook, opts WebhookSyncOptions) (WebhookPlan, error) {
+	return t.SyncWebhooksContext(context.Background(), desired, opts)
+}
+
+// SyncWebhooksContext - SyncWebhooks honouring the cancellation and deadline of ctx
+//
+// When a change fails the error is returned with the plan, whose Done flags
+// tell which changes were applied before it.
+func (t *TauAPI) SyncWebhooksContext(ctx context.Context, desired []Webhook, opts WebhookSyncOptions) (WebhookPlan, error) {
+	registered, err := t.GetWebhooksContext(ctx)
+	if err != nil {
+		return WebhookPlan{DryRun: opts.DryRun}, fmt.Errorf("SyncWebhooks-> %w", err)
+	}
+	plan, err := planWebhooks(registered, desired, opts)
+	if err != nil || opts.DryRun {
+		return plan, err
+	}
+	for i := range plan.Changes {
+		c := &plan.Changes[i]
+		switch c.Action {
+		case WebhookDelete:
+			err = t.DeleteWebhookContext(ctx, c.Current.ID)
+		case WebhookUpdate:
+			_, err = t.UpdateWebhookContext(ctx, c.Current.ID, c.Desired)
+		case WebhookCreate:
+			_, err = t.CreateWebhookContext(ctx, c.Desired)
+		}
+		if err != nil {
+			return plan, fmt.Errorf("SyncWebhooks-> %s %s: %w", c.Action, c.webhook().Name, err)
+		}
+		c.Done = true
+	}
+	return plan, nil
+}
@@ -0,0 +1,192 @@
+package taurosapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeWebhooks serves the webhook endpoints from an in-memory list
+type fakeWebhooks struct {
+	hooks  []Webhook
+	nextID int64
+	calls  []string
+}
+
+func (f *fakeWebhooks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.calls = append(f.calls, r.Method+" "+r.URL.Path)
+	body, _ := ioutil.ReadAll(r.Body)
+	switch {
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/webhooks/webhooks/"):
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": f.hooks})
+	case r.Method == "POST":
+		if len(f.hooks) >= MaxWebhooks {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`["Limit reached"]`))
+			return
+		}
+		var hook Webhook
+		json.Unmarshal(body, &hook)
+		f.nextID++
+		hook.ID = f.nextID
+		f.hooks = append(f.hooks, hook)
+		json.NewEncoder(w).Encode(hook)
+	default:
+		for i, hook := range f.hooks {
+			if !strings.HasSuffix(r.URL.Path, "/"+strconv.FormatInt(hook.ID, 10)+"/") {
+				continue
+			}
+			switch r.Method {
+			case "DELETE":
+				f.hooks = append(f.hooks[:i], f.hooks[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			case "GET":
+				json.NewEncoder(w).Encode(hook)
+				return
+			}
+			json.Unmarshal(body, &f.hooks[i])
+			f.hooks[i].ID = hook.ID
+			json.NewEncoder(w).Encode(f.hooks[i])
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"detail":"Not found."}`))
+	}
+}
+
+func TestSyncWebhooks(t *testing.T) {
+	fake := &fakeWebhooks{nextID: 10}
+	for _, name := range []string{"keep", "change", "old1", "old2", "keep"} {
+		fake.nextID++
+		fake.hooks = append(fake.hooks, Webhook{ID: fake.nextID, Name: name, Endpoint: "https://a.example/" + name, NotifyTrade: true, IsActive: true})
+	}
+	c, _ := newTestClient(t, fake)
+
+	desired := []Webhook{
+		{Name: "keep", Endpoint: "https://a.example/keep", NotifyTrade: true, IsActive: true},
+		{Name: "change", Endpoint: "https://a.example/change", NotifyTrade: true, NotifyDeposit: true, IsActive: true},
+		{Name: "new", Endpoint: "https://a.example/new", NotifyDeposit: true, IsActive: true},
+	}
+	plan, err := c.SyncWebhooks(desired, WebhookSyncOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "delete old1 https://a.example/old1 (id 13)\n" + //room for the creation
+		"create new https://a.example/new\n" +
+		"update change https://a.example/change (id 12)\n" +
+		"delete old2 https://a.example/old2 (id 14)\n" +
+		"delete keep https://a.example/keep (id 15)\n"
+	if plan.String() != want {
+		t.Errorf("unexpected plan:\n%s", plan)
+	}
+	if len(fake.calls) != 1 || len(fake.hooks) != 5 {
+		t.Errorf("dry run changed the webhooks: %v", fake.calls)
+	}
+
+	if plan, err = c.SyncWebhooks(desired, WebhookSyncOptions{}); err != nil {
+		t.Fatalf("%v\n%s", err, plan)
+	}
+	for _, ch := range plan.Changes {
+		if !ch.Done {
+			t.Errorf("change not applied: %+v", ch)
+		}
+	}
+	if plan, err = c.SyncWebhooks(desired, WebhookSyncOptions{}); err != nil || !plan.Empty() || len(plan.Unchanged) != 3 {
+		t.Errorf("second sync not empty: %v\n%s", err, plan)
+	}
+}
+
+func TestSyncWebhooksCreatesFirst(t *testing.T) {
+	fake := &fakeWebhooks{nextID: 1, hooks: []Webhook{{ID: 1, Name: "hook", Endpoint: "https://a.example/old", NotifyTrade: true, IsActive: true}}}
+	c, _ := newTestClient(t, fake)
+	desired := []Webhook{{Name: "hook", Endpoint: "https://b.example/new", NotifyTrade: true}}
+	plan, err := c.SyncWebhooks(desired, WebhookSyncOptions{})
+	if err != nil {
+		t.Fatalf("%v\n%s", err, plan)
+	}
+	want := "create hook https://b.example/new\n" +
+		"delete hook https://a.example/old (id 1)\n"
+	if plan.String() != want {
+		t.Errorf("moved webhook not created before the old one is deleted:\n%s", plan)
+	}
+	if len(fake.hooks) != 1 || fake.hooks[0].Endpoint != "https://b.example/new" {
+		t.Errorf("unexpected webhooks %+v", fake.hooks)
+	}
+}
+
+func TestSyncWebhooksRejects(t *testing.T) {
+	c, _ := newTestClient(t, &fakeWebhooks{})
+	tooMany := make([]Webhook, MaxWebhooks+1)
+	for i := range tooMany {
+		tooMany[i].Name = string(rune('a' + i))
+	}
+	if _, err := c.SyncWebhooks(tooMany, WebhookSyncOptions{DryRun: true}); !errors.Is(err, ErrWebhookLimit) {
+		t.Errorf("expected limit error, got %v", err)
+	}
+	twice := []Webhook{{Name: "a", Endpoint: "e"}, {Name: "a", Endpoint: "e"}}
+	if _, err := c.SyncWebhooks(twice, WebhookSyncOptions{DryRun: true}); err == nil {
+		t.Error("expected error for duplicated desired webhook")
+	}
+}
+
+func TestSyncWebhooksActive(t *testing.T) {
+	fake := &fakeWebhooks{nextID: 1, hooks: []Webhook{{ID: 1, Name: "paused", Endpoint: "https://a.example/paused", NotifyTrade: true}}}
+	c, _ := newTestClient(t, fake)
+
+	desired := []Webhook{ //IsActive left unset
+		{Name: "paused", Endpoint: "https://a.example/paused", NotifyTrade: true},
+		{Name: "new", Endpoint: "https://a.example/new", NotifyTrade: true},
+	}
+	plan, err := c.SyncWebhooks(desired, WebhookSyncOptions{})
+	if err != nil || len(plan.Changes) != 1 || plan.Changes[0].Action != WebhookCreate {
+		t.Fatalf("unexpected plan %v:\n%s", err, plan)
+	}
+	if fake.hooks[0].IsActive || !fake.hooks[1].IsActive {
+		t.Errorf("paused webhook activated or new one created inactive: %+v", fake.hooks)
+	}
+	desired[0].IsActive, desired[1].IsActive = true, true
+	if plan, err = c.SyncWebhooks(desired, WebhookSyncOptions{SyncActive: true}); err != nil || len(plan.Changes) != 1 || plan.Changes[0].Current.ID != 1 {
+		t.Fatalf("unexpected plan %v:\n%s", err, plan)
+	}
+	if !fake.hooks[0].IsActive {
+		t.Error("SyncActive did not activate the paused webhook")
+	}
+}
+
+func TestWebhookJSONTags(t *testing.T) {
+	data, _ := json.Marshal(Webhook{NotifyOrderPlaced: true, NotifyOrderFilled: true})
+	for _, tag := range []string{`"notify_order_placed":true`, `"notify_order_filled":true`} {
+		if !strings.Contains(string(data), tag) {
+			t.Errorf("%s missing from %s", tag, data)
+		}
+	}
+}
+
+func TestWebhookGetUpdateActive(t *testing.T) {
+	fake := &fakeWebhooks{hooks: []Webhook{{ID: 7, Name: "hook", Endpoint: "https://a.example", AuthorizationHeader: "X-Auth", AuthorizationContent: "old", NotifyOrderFilled: true, IsActive: true}}}
+	c, _ := newTestClient(t, fake)
+
+	w, err := c.GetWebhook(7)
+	if err != nil || w.AuthorizationContent != "old" || !w.NotifyOrderFilled {
+		t.Fatalf("GetWebhook: %+v, %v", w, err)
+	}
+	w.AuthorizationContent = "new"
+	if w, err = c.UpdateWebhook(7, w); err != nil || w.AuthorizationContent != "new" || !w.IsActive {
+		t.Errorf("UpdateWebhook: %+v, %v", w, err)
+	}
+	if w, err = c.SetWebhookActive(7, false); err != nil || w.IsActive || w.AuthorizationContent != "new" {
+		t.Errorf("SetWebhookActive: %+v, %v", w, err)
+	}
+	if _, err = c.GetWebhook(8); err == nil {
+		t.Error("expected error for unknown webhook")
+	}
+	want := []string{"GET /api/v2/webhooks/webhooks/7/", "PUT /api/v2/webhooks/webhooks/7/", "PATCH /api/v2/webhooks/webhooks/7/", "GET /api/v2/webhooks/webhooks/8/"}
+	if strings.Join(fake.calls, ",") != strings.Join(want, ",") {
+		t.Errorf("unexpected calls %v", fake.calls)
+	}
+}
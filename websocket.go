@@ -0,0 +1,357 @@
+package taurosapi
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// minimal RFC 6455 client, enough for the JSON text streams sent by Tauros
+
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xa
+
+	wsMaxMessageSize = 16 << 20
+	wsAcceptGUID     = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// errWsClosed is returned by ReadMessage once the peer closed the connection
+var errWsClosed = errors.New("websocket: connection closed")
+
+type wsConn struct {
+	conn     net.Conn
+	br       *bufio.Reader
+	isClient bool // clients mask the frames they send
+
+	readTimeout time.Duration // extended on every frame received, 0 disables
+
+	writeMu sync.Mutex
+}
+
+// dialWebsocket opens a websocket connection to rawURL (ws:// or wss://)
+// sending header with the upgrade request; the connection goes through the
+// dialer, proxy and TLS settings of tr, or of http.DefaultTransport when nil
+func dialWebsocket(ctx context.Context, tr *http.Transport, rawURL string, header http.Header) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("dialWebsocket-> %w", err)
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return nil, fmt.Errorf("dialWebsocket-> unsupported scheme %q", u.Scheme)
+	}
+	if tr == nil {
+		tr, _ = http.DefaultTransport.(*http.Transport)
+	}
+	conn, err := wsDial(ctx, tr, u)
+	if err != nil {
+		return nil, fmt.Errorf("dialWebsocket-> %w", err)
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("dialWebsocket-> %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        u,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Host:       u.Host,
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("dialWebsocket-> write handshake: %w", err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("dialWebsocket-> read handshake: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, fmt.Errorf("dialWebsocket-> handshake rejected: %s", resp.Status)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != wsAcceptKey(key) {
+		conn.Close()
+		return nil, errors.New("dialWebsocket-> invalid Sec-WebSocket-Accept")
+	}
+	conn.SetDeadline(time.Time{})
+	return &wsConn{conn: conn, br: br, isClient: true}, nil
+}
+
+// wsDial opens the tcp connection of u, tunnelled through the proxy of tr if
+// any and wrapped in TLS for wss; the deadline of ctx applies to the handshakes
+func wsDial(ctx context.Context, tr *http.Transport, u *url.URL) (net.Conn, error) {
+	httpURL := *u //proxy selection looks at the http scheme
+	httpURL.Scheme = map[string]string{"ws": "http", "wss": "https"}[u.Scheme]
+	var proxyURL *url.URL
+	dial := (&net.Dialer{}).DialContext
+	tlsConfig := &tls.Config{}
+	if tr != nil {
+		if tr.DialContext != nil {
+			dial = tr.DialContext
+		}
+		if tr.TLSClientConfig != nil {
+			tlsConfig = tr.TLSClientConfig.Clone()
+		}
+		if tr.Proxy != nil {
+			var err error
+			if proxyURL, err = tr.Proxy(&http.Request{Method: http.MethodGet, URL: &httpURL, Header: http.Header{}}); err != nil {
+				return nil, fmt.Errorf("proxy: %w", err)
+			}
+		}
+	}
+	addr := hostPort(&httpURL)
+	if proxyURL != nil {
+		if proxyURL.Scheme != "http" {
+			return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+		}
+		addr = hostPort(proxyURL)
+	}
+	conn, err := dial(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if proxyURL != nil {
+		if err := wsConnect(conn, proxyURL, hostPort(&httpURL)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if u.Scheme == "wss" {
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = u.Hostname()
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("tls handshake: %w", err)
+		}
+		conn = tlsConn
+	}
+	return conn, nil
+}
+
+// wsConnect asks the http proxy at the other end of conn for a tunnel to addr
+func wsConnect(conn net.Conn, proxyURL *url.URL, addr string) error {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if user := proxyURL.User; user != nil {
+		pass, _ := user.Password()
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(user.Username()+":"+pass)))
+	}
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("proxy connect: %w", err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return fmt.Errorf("proxy connect: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy connect rejected: %s", resp.Status)
+	}
+	if br.Buffered() > 0 {
+		return errors.New("proxy connect: unexpected data after the response")
+	}
+	return nil
+}
+
+// hostPort is the host of u with the default port of its scheme when none is given
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "https" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+	return net.JoinHostPort(u.Hostname(), "80")
+}
+
+func wsAcceptKey(key string) string {
+	h := sha1.Sum([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// ReadMessage returns the next text or binary message, answering pings and
+// skipping pongs on the way
+func (c *wsConn) ReadMessage() (opcode int, payload []byte, err error) {
+	var message []byte
+	msgOp := -1
+	for {
+		if c.readTimeout > 0 {
+			c.conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+		}
+		fin, op, data, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch op {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, data); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			c.writeFrame(wsOpClose, data)
+			return 0, nil, errWsClosed
+		case wsOpText, wsOpBinary:
+			if msgOp != -1 {
+				return 0, nil, errors.New("websocket: unexpected data frame inside fragmented message")
+			}
+			msgOp = op
+		case wsOpContinuation:
+			if msgOp == -1 {
+				return 0, nil, errors.New("websocket: unexpected continuation frame")
+			}
+		default:
+			return 0, nil, fmt.Errorf("websocket: unknown opcode %d", op)
+		}
+		if len(message)+len(data) > wsMaxMessageSize {
+			return 0, nil, errors.New("websocket: message too large")
+		}
+		message = append(message, data...)
+		if fin {
+			return msgOp, message, nil
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (fin bool, opcode int, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = head[0]&0x80 != 0
+	opcode = int(head[0] & 0x0f)
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > wsMaxMessageSize {
+		return false, 0, nil, errors.New("websocket: frame too large")
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage sends data in a single frame
+func (c *wsConn) WriteMessage(opcode int, data []byte) error {
+	return c.writeFrame(opcode, data)
+}
+
+func (c *wsConn) writeFrame(opcode int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	frame := make([]byte, 0, len(data)+14)
+	frame = append(frame, 0x80|byte(opcode))
+	maskBit := byte(0)
+	if c.isClient {
+		maskBit = 0x80
+	}
+	switch n := len(data); {
+	case n < 126:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xffff:
+		frame = append(frame, maskBit|126, byte(n>>8), byte(n))
+	default:
+		var ext [8]byte
+		binary.BigEndian.PutUint64(ext[:], uint64(n))
+		frame = append(frame, maskBit|127)
+		frame = append(frame, ext[:]...)
+	}
+	if c.isClient {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		start := len(frame)
+		frame = append(frame, data...)
+		for i := range frame[start:] {
+			frame[start+i] ^= mask[i%4]
+		}
+	} else {
+		frame = append(frame, data...)
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// Close sends a normal closure frame and closes the connection
+func (c *wsConn) Close() error {
+	c.writeFrame(wsOpClose, []byte{0x03, 0xe8}) //1000 normal closure
+	return c.conn.Close()
+}
+
+// isWsTimeout tells whether err comes from an expired read deadline
+func isWsTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
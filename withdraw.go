@@ -0,0 +1,180 @@
+package taurosapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrWithdrawalBelowMinimum - the amount is under Coin.MinWithdrawal or does not cover the fee
+var ErrWithdrawalBelowMinimum = errors.New("tauros: withdrawal below minimum")
+
+// WithdrawOptions - optional parameters of Withdraw
+type WithdrawOptions struct {
+	Tag string // destination tag or memo, for coins such as XRP and XLM
+	Nip string // security pin of the account, as for Transfer
+}
+
+// WithdrawalPreview - what a withdrawal would cost
+type WithdrawalPreview struct {
+	Coin    string
+	Amount  Decimal // amount debited from the wallet
+	Fee     Decimal // Coin.FeeWithdrawal
+	Net     Decimal // amount received at the address, Amount - Fee
+	Minimum Decimal // Coin.MinWithdrawal
+}
+
+// PreviewWithdrawal - fee and net amount of withdrawing amount of the coin
+func (c Coin) PreviewWithdrawal(amount Decimal) (WithdrawalPreview, error) {
+	p := WithdrawalPreview{
+		Coin:    c.Coin,
+		Amount:  amount,
+		Fee:     c.FeeWithdrawal,
+		Net:     amount.Sub(c.FeeWithdrawal),
+		Minimum: c.MinWithdrawal,
+	}
+	if amount.LessThan(c.MinWithdrawal) {
+		return p, fmt.Errorf("withdrawal of %s %s, minimum is %s: %w", amount, c.Coin, c.MinWithdrawal, ErrWithdrawalBelowMinimum)
+	}
+	if p.Net.Sign() <= 0 {
+		return p, fmt.Errorf("withdrawal of %s %s does not cover the fee of %s: %w", amount, c.Coin, c.FeeWithdrawal, ErrWithdrawalBelowMinimum)
+	}
+	return p, nil
+}
+
+// PreviewWithdrawal - fee and net amount of withdrawing amount of coin, using the limits returned by GetCoins
+func (t *TauAPI) PreviewWithdrawal(coin string, amount Decimal) (WithdrawalPreview, error) {
+	return t.PreviewWithdrawalContext(context.Background(), coin, amount)
+}
+
+// PreviewWithdrawalContext - PreviewWithdrawal honouring the cancellation and deadline of ctx
+func (t *TauAPI) PreviewWithdrawalContext(ctx context.Context, coin string, amount Decimal) (WithdrawalPreview, error) {
+	coins, err := t.GetCoinsContext(ctx)
+	if err != nil {
+		return WithdrawalPreview{}, fmt.Errorf("PreviewWithdrawal-> %w", err)
+	}
+	for _, c := range coins {
+		if strings.EqualFold(c.Coin, coin) {
+			return c.PreviewWithdrawal(amount)
+		}
+	}
+	return WithdrawalPreview{}, fmt.Errorf("PreviewWithdrawal-> unknown coin %q", coin)
+}
+
+var (
+	base58Chars  = "[1-9A-HJ-NP-Za-km-z]" //the ripple alphabet has the same characters in another order
+	addressRegex = map[string]*regexp.Regexp{
+		"BTC":  regexp.MustCompile(`^([13]` + base58Chars + `{25,34}|bc1[02-9ac-hj-np-z]{11,71})$`),
+		"LTC":  regexp.MustCompile(`^([LM3]` + base58Chars + `{26,33}|ltc1[02-9ac-hj-np-z]{11,71})$`),
+		"BCH":  regexp.MustCompile(`^((bitcoincash:)?[qp][02-9ac-hj-np-z]{41}|[13]` + base58Chars + `{25,34})$`),
+		"DASH": regexp.MustCompile(`^[X7]` + base58Chars + `{33}$`),
+		"ETH":  regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`),
+		"XRP":  regexp.MustCompile(`^r` + base58Chars + `{24,34}$`),
+		"XLM":  regexp.MustCompile(`^G[A-Z2-7]{55}$`),
+	}
+	// tokens withdrawn to an address of the chain they live on
+	tokenChains = map[string]string{
+		"DAI":  "ETH",
+		"USDT": "ETH",
+		"USDC": "ETH",
+		"BAT":  "ETH",
+	}
+)
+
+// ValidateAddress - check the format of a withdrawal address and tag for coin
+//
+// Only the shape is checked (alphabet, prefix and length), not the checksum;
+// coins without a known format just need an address without spaces.
+func ValidateAddress(coin, address, tag string) error {
+	coin = strings.ToUpper(strings.TrimSpace(coin))
+	if address == "" || strings.ContainsAny(address, " \t\r\n") {
+		return fmt.Errorf("invalid %s address %q", coin, address)
+	}
+	chain := coin
+	if c, ok := tokenChains[coin]; ok {
+		chain = c
+	}
+	if re, ok := addressRegex[chain]; ok && !re.MatchString(address) {
+		return fmt.Errorf("invalid %s address %q", coin, address)
+	}
+	switch chain {
+	case "XRP":
+		if _, err := strconv.ParseUint(tag, 10, 32); tag != "" && err != nil {
+			return fmt.Errorf("invalid XRP destination tag %q", tag)
+		}
+	case "XLM":
+		if len(tag) > 28 {
+			return fmt.Errorf("XLM memo %q longer than 28 bytes", tag)
+		}
+	default:
+		if tag != "" {
+			return fmt.Errorf("%s withdrawals take no tag or memo", coin)
+		}
+	}
+	return nil
+}
+
+// withdrawMsg - json for a crypto withdrawal
+type withdrawMsg struct {
+	Coin    string  `json:"coin"`
+	Address string  `json:"address"`
+	Amount  Decimal `json:"amount"`
+	Tag     string  `json:"tag,omitempty"`
+	Nip     string  `json:"nip,omitempty"`
+}
+
+// Withdraw - send amount of coin to an external address
+//
+// The address and tag are validated with ValidateAddress, the amount with
+// PreviewWithdrawal and the movement with the Policy of the client before the
+// request is signed; the fee is taken from amount.
+func (t *TauAPI) Withdraw(coin, address string, amount Decimal, opts WithdrawOptions) (Withdrawal, error) {
+	return t.WithdrawContext(context.Background(), coin, address, amount, opts)
+}
+
+// WithdrawContext - Withdraw honouring the cancellation and deadline of ctx
+func (t *TauAPI) WithdrawContext(ctx context.Context, coin, address string, amount Decimal, opts WithdrawOptions) (Withdrawal, error) {
+	var w Withdrawal
+	if err := ValidateAddress(coin, address, opts.Tag); err != nil {
+		return w, fmt.Errorf("Withdraw-> %w", err)
+	}
+	if _, err := t.PreviewWithdrawalContext(ctx, coin, amount); err != nil {
+		return w, fmt.Errorf("Withdraw-> %w", err)
+	}
+	release, err := t.checkPolicy(ctx, Outgoing{
+		Kind:        OutgoingWithdrawal,
+		Coin:        coin,
+		Amount:      amount,
+		Destination: address,
+		Tag:         opts.Tag,
+	})
+	if err != nil {
+		return w, fmt.Errorf("Withdraw-> %w", err)
+	}
+	jsonPostMsg, _ := json.Marshal(withdrawMsg{
+		Coin:    strings.ToUpper(coin),
+		Address: address,
+		Amount:  amount,
+		Tag:     opts.Tag,
+		Nip:     opts.Nip,
+	})
+	jsonData, err := t.doTauRequest(ctx, &TauReq{
+		Version:   2,
+		Method:    "POST",
+		Path:      "wallets/crypto-withdraw",
+		NeedsAuth: true,
+		PostMsg:   jsonPostMsg,
+	})
+	release(err)
+	if err != nil {
+		return w, fmt.Errorf("Withdraw-> %w", err)
+	}
+	if err := json.Unmarshal(jsonData, &w); err != nil {
+		return w, fmt.Errorf("Withdraw-> unmarshal jsonData %w", err)
+	}
+	return w, nil
+}
@@ -0,0 +1,96 @@
+package taurosapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestValidateAddress(t *testing.T) {
+	valid := []struct{ coin, address, tag string }{
+		{"btc", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", ""},
+		{"BTC", "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", ""},
+		{"BTC", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", ""},
+		{"ETH", "0x32Be343B94f860124dC4fEe278FDCBD38C102D88", ""},
+		{"DAI", "0x32be343b94f860124dc4fee278fdcbd38c102d88", ""},
+		{"XRP", "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh", "12345"},
+		{"XLM", "GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A", "memo"},
+		{"MXN", "012180001234567891", ""},
+	}
+	for _, v := range valid {
+		if err := ValidateAddress(v.coin, v.address, v.tag); err != nil {
+			t.Errorf("%s %s: %v", v.coin, v.address, err)
+		}
+	}
+	invalid := []struct{ coin, address, tag string }{
+		{"BTC", "", ""},
+		{"BTC", "0x32Be343B94f860124dC4fEe278FDCBD38C102D88", ""},
+		{"BTC", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN0", ""}, //0 is not base58
+		{"BTC", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "memo"},
+		{"ETH", "0x32Be343B94f860124dC4fEe278FDCBD38C102D8", ""},
+		{"XRP", "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh", "tag"},
+		{"XLM", "GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A", "a memo far longer than allowed"},
+		{"MXN", "0121 8000", ""},
+	}
+	for _, v := range invalid {
+		if err := ValidateAddress(v.coin, v.address, v.tag); err == nil {
+			t.Errorf("%s %q tag %q: expected error", v.coin, v.address, v.tag)
+		}
+	}
+}
+
+func TestPreviewWithdrawal(t *testing.T) {
+	btc := Coin{Coin: "BTC", MinWithdrawal: MustDecimal("0.001"), FeeWithdrawal: MustDecimal("0.0005")}
+	p, err := btc.PreviewWithdrawal(MustDecimal("0.01"))
+	if err != nil || !p.Net.Equal(MustDecimal("0.0095")) || !p.Fee.Equal(MustDecimal("0.0005")) {
+		t.Errorf("unexpected preview %+v, %v", p, err)
+	}
+	if _, err := btc.PreviewWithdrawal(MustDecimal("0.0009")); !errors.Is(err, ErrWithdrawalBelowMinimum) {
+		t.Errorf("expected ErrWithdrawalBelowMinimum, got %v", err)
+	}
+	btc.MinWithdrawal = Decimal{}
+	if _, err := btc.PreviewWithdrawal(MustDecimal("0.0005")); !errors.Is(err, ErrWithdrawalBelowMinimum) {
+		t.Errorf("expected ErrWithdrawalBelowMinimum when the fee eats the amount, got %v", err)
+	}
+}
+
+func TestWithdraw(t *testing.T) {
+	var withdrawals []withdrawMsg
+	c, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/coins":
+			w.Write([]byte(`{"success":true,"payload":{"cryto":[{"coin":"XRP","min_withdraw":"20","fee_withdraw":"0.1"}],"fiat":[]}}`))
+		case "/api/v2/wallets/crypto-withdraw/":
+			var msg withdrawMsg
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, &msg)
+			withdrawals = append(withdrawals, msg)
+			w.Write([]byte(`{"success":true,"payload":{"id":5,"coin":"XRP","amount":"25","fee_amount":"0.1","address":"` + msg.Address + `"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	address := "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh"
+
+	w, err := c.Withdraw("xrp", address, MustDecimal("25"), WithdrawOptions{Tag: "42", Nip: "1234"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.ID != 5 || !w.FeeAmount.Equal(MustDecimal("0.1")) {
+		t.Errorf("unexpected withdrawal %+v", w)
+	}
+	if len(withdrawals) != 1 || withdrawals[0].Coin != "XRP" || withdrawals[0].Tag != "42" || withdrawals[0].Nip != "1234" {
+		t.Errorf("unexpected request %+v", withdrawals)
+	}
+	if _, err := c.Withdraw("XRP", address, MustDecimal("10"), WithdrawOptions{}); !errors.Is(err, ErrWithdrawalBelowMinimum) {
+		t.Errorf("expected ErrWithdrawalBelowMinimum, got %v", err)
+	}
+	if _, err := c.Withdraw("XRP", "not an address", MustDecimal("25"), WithdrawOptions{}); err == nil {
+		t.Error("expected address error")
+	}
+	if len(withdrawals) != 1 {
+		t.Errorf("invalid withdrawals were sent: %+v", withdrawals)
+	}
+}
@@ -0,0 +1,189 @@
+package taurosapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Websocket endpoints of the Tauros deployments
+const (
+	WsProduction = "wss://ws.tauros.io/ws/v1/"
+	WsStaging    = "wss://ws.staging.tauros.io/ws/v1/"
+)
+
+// Event types of the notifications sent through websockets and webhooks
+const (
+	EventDeposit       = "deposit"
+	EventWithdrawal    = "withdrawal"
+	EventOrderPlaced   = "order_placed"
+	EventOrderFilled   = "order_filled"
+	EventTrade         = "trade"
+	EventInnerTransfer = "inner_transfer"
+)
+
+// Websocket channels of the user notifications
+const (
+	WsChannelOrders      = "orders"
+	WsChannelTrades      = "trades"
+	WsChannelDeposits    = "deposits"
+	WsChannelWithdrawals = "withdrawals"
+)
+
+// WsClient - authenticated websocket connection delivering the order, trade,
+// deposit and withdrawal notifications of the user
+//
+// Register callbacks with On/OnAny or read from Messages, then call Run. The
+// connection is kept alive with pings, reconnected with backoff when it drops
+// or goes stale, and resubscribed on every reconnection.
+type WsClient struct {
+	URL          string          // websocket endpoint, default WsProduction
+	Transport    *http.Transport // dialer, proxy and TLS settings of the connection, nil for those of http.DefaultTransport
+	Channels     []string        // channels subscribed on each connection, default all user channels
+	PingInterval time.Duration   // default (or when not positive) 15 seconds
+	StaleTimeout time.Duration   // reconnect when nothing is received for this long, default (or when not positive) 45 seconds
+	Backoff      RetryPolicy     // reconnection delays, MaxAttempts is ignored; delays not positive default to 1 and 30 seconds
+	Logger       Logger          // connection errors, nil to discard
+
+	signer *Signer
+	nonces NonceSource
+
+	mu       sync.Mutex
+	handlers map[string][]func(Event)
+	any      []func(Event)
+	messages chan Event // created by NewWsClient and again each time Run returns
+	consumed bool       // Messages was called, so messages are sent to the channel
+	running  bool
+}
+
+// wsMessageBuffer - messages the channel of WsClient.Messages holds before it holds back the connection
+const wsMessageBuffer = 64
+
+// NewWsClient - websocket client for the given credentials
+func NewWsClient(apiKey, apiSecret string) (*WsClient, error) {
+	signer, err := NewSigner(apiKey, apiSecret)
+	if err != nil {
+		return nil, fmt.Errorf("NewWsClient-> %w", err)
+	}
+	return &WsClient{
+		URL:          WsProduction,
+		Channels:     []string{WsChannelOrders, WsChannelTrades, WsChannelDeposits, WsChannelWithdrawals},
+		PingInterval: wsDefaultPingInterval,
+		StaleTimeout: wsDefaultStaleTimeout,
+		Backoff:      RetryPolicy{BaseDelay: wsDefaultBaseDelay, MaxDelay: wsDefaultMaxDelay},
+		signer:       signer,
+		nonces:       NewMonotonicNonce(),
+		handlers:     make(map[string][]func(Event)),
+		messages:     make(chan Event, wsMessageBuffer),
+	}, nil
+}
+
+// On - call fn for every message of the given event type (EventOrderPlaced, EventTrade, ...)
+func (c *WsClient) On(eventType string, fn func(Event)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[eventType] = append(c.handlers[eventType], fn)
+}
+
+// OnAny - call fn for every message
+func (c *WsClient) OnAny(fn func(Event)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.any = append(c.any, fn)
+}
+
+// Messages - channel receiving every message from the first call on, closed
+// when Run returns; keep draining it, as a full channel holds back the
+// connection. Once Run returned, Messages gives the channel of the next Run.
+func (c *WsClient) Messages() <-chan Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.consumed = true
+	return c.messages
+}
+
+// Run - connect and deliver messages until ctx is done, then close the connection and return ctx.Err()
+func (c *WsClient) Run(ctx context.Context) error {
+	c.mu.Lock()
+	if c.running {
+		c.mu.Unlock()
+		return errors.New("WsClient-> already running")
+	}
+	c.running = true
+	messages := c.messages
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.running = false
+		close(c.messages)
+		c.messages = make(chan Event, wsMessageBuffer)
+		c.mu.Unlock()
+	}()
+
+	s := &wsSession{
+		url:          c.URL,
+		transport:    c.Transport,
+		header:       c.authHeader,
+		onConnect:    c.subscribe,
+		pingInterval: c.PingInterval,
+		staleTimeout: c.StaleTimeout,
+		backoff:      c.Backoff,
+		logger:       c.Logger,
+		onMessage: func(data []byte) error {
+			var msg Event
+			if err := json.Unmarshal(data, &msg); err != nil {
+				return fmt.Errorf("decode message: %w", err)
+			}
+			if msg.Type == "" { //subscription acks and other control messages
+				return nil
+			}
+			c.dispatch(ctx, msg, messages)
+			return nil
+		},
+	}
+	return s.run(ctx)
+}
+
+// authHeader signs the upgrade request like a REST call: GET + path of the endpoint
+func (c *WsClient) authHeader() (http.Header, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	nonce := strconv.FormatInt(c.nonces.Nonce(), 10)
+	h := http.Header{}
+	h.Set("Authorization", "Bearer "+c.signer.APIKey)
+	h.Set("Taur-Nonce", nonce)
+	h.Set("Taur-Signature", c.signer.Sign(http.MethodGet, u.RequestURI(), nil, nonce))
+	return h, nil
+}
+
+func (c *WsClient) subscribe(ctx context.Context, conn *wsConn) error {
+	msg, _ := json.Marshal(struct {
+		Action   string   `json:"action"`
+		Channels []string `json:"channels"`
+	}{"subscribe", c.Channels})
+	return conn.WriteMessage(wsOpText, msg)
+}
+
+func (c *WsClient) dispatch(ctx context.Context, msg Event, messages chan Event) {
+	c.mu.Lock()
+	handlers := append(append([]func(Event){}, c.handlers[msg.Type]...), c.any...)
+	consumed := c.consumed
+	c.mu.Unlock()
+	for _, fn := range handlers {
+		fn(msg)
+	}
+	if consumed {
+		select {
+		case messages <- msg:
+		case <-ctx.Done():
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package taurosapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// acceptWebsocket upgrades a test server request to a server side wsConn
+func acceptWebsocket(t *testing.T, w http.ResponseWriter, r *http.Request) *wsConn {
+	conn, brw, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsAcceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+	brw.Flush()
+	return &wsConn{conn: conn, br: bufio.NewReader(brw)}
+}
+
+func TestWsClientReconnectsAndResubscribes(t *testing.T) {
+	signer, _ := NewSigner("key", "c2VjcmV0")
+	var connections, subscriptions int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, _ := signer.VerifyRequest(r); !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := atomic.AddInt32(&connections, 1)
+		conn := acceptWebsocket(t, w, r)
+		defer conn.conn.Close()
+		_, data, err := conn.ReadMessage()
+		if err != nil || !strings.Contains(string(data), `"subscribe"`) {
+			t.Errorf("expected subscription, got %s %v", data, err)
+			return
+		}
+		atomic.AddInt32(&subscriptions, 1)
+		conn.WriteMessage(wsOpPing, []byte("hi"))
+		msg, _ := json.Marshal(map[string]interface{}{
+			"type":   EventOrderFilled,
+			"title":  "order filled",
+			"object": map[string]interface{}{"id": n, "market": "btc-mxn", "filled": "0.5"},
+		})
+		conn.WriteMessage(wsOpText, msg)
+		if n == 1 {
+			return //drop the first connection
+		}
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	c, err := NewWsClient("key", "c2VjcmV0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.URL = "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws/v1/"
+	c.Backoff = RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	var filled int32
+	c.On(EventOrderFilled, func(m Event) { atomic.AddInt32(&filled, 1) })
+	messages := c.Messages()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+	for i := int64(1); i <= 2; i++ {
+		select {
+		case m := <-messages:
+			p, err := m.Payload()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o, ok := p.(*OrderFilledEvent); !ok || o.ID != i || o.Market != "BTC-MXN" || o.Filled.String() != "0.5" {
+				t.Errorf("unexpected message %+v", p)
+			}
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for messages")
+		}
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("unexpected Run error %v", err)
+	}
+	if _, open := <-messages; open {
+		t.Error("messages channel not closed")
+	}
+	if atomic.LoadInt32(&filled) != 2 || atomic.LoadInt32(&subscriptions) != 2 {
+		t.Errorf("expected 2 messages and 2 subscriptions, got %d and %d", filled, subscriptions)
+	}
+}
+
+func TestWsClientStaleConnection(t *testing.T) {
+	var connections int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&connections, 1)
+		conn := acceptWebsocket(t, w, r)
+		defer conn.conn.Close()
+		time.Sleep(200 * time.Millisecond) //never answer pings
+	}))
+	defer srv.Close()
+	c, _ := NewWsClient("key", "c2VjcmV0")
+	c.URL = "ws" + strings.TrimPrefix(srv.URL, "http")
+	c.PingInterval = 10 * time.Millisecond
+	c.StaleTimeout = 30 * time.Millisecond
+	c.Backoff = RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	c.Run(ctx)
+	if atomic.LoadInt32(&connections) < 2 {
+		t.Errorf("stale connection not replaced, %d connections", connections)
+	}
+}
+
+func TestWsClientZeroConfig(t *testing.T) {
+	var connections int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&connections, 1)
+		acceptWebsocket(t, w, r).conn.Close() //drop every connection
+	}))
+	defer srv.Close()
+	c, _ := NewWsClient("key", "c2VjcmV0")
+	c.URL = "ws" + strings.TrimPrefix(srv.URL, "http")
+	c.PingInterval, c.StaleTimeout, c.Backoff = 0, 0, RetryPolicy{}
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if err := c.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("unexpected Run error %v", err)
+	}
+	if n := atomic.LoadInt32(&connections); n < 1 || n > 5 {
+		t.Errorf("%d connections without the default backoff", n)
+	}
+}
+
+func TestWsClientTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn := acceptWebsocket(t, w, r)
+		defer conn.conn.Close()
+		conn.ReadMessage() //subscription
+		for i := 0; i < 20; i++ {
+			conn.WriteMessage(wsOpText, []byte(`{"type":"trade","object":{"id":1}}`))
+			time.Sleep(5 * time.Millisecond)
+		}
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+	var tunnels int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect || r.Host != strings.TrimPrefix(srv.URL, "http://") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		atomic.AddInt32(&tunnels, 1)
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		defer conn.Close()
+		conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		go io.Copy(upstream, conn)
+		io.Copy(conn, upstream)
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+	var dials int32
+	c, _ := NewWsClient("key", "c2VjcmV0")
+	c.URL = "ws" + strings.TrimPrefix(srv.URL, "http")
+	c.Transport = &http.Transport{
+		Proxy: http.ProxyURL(proxyURL),
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			atomic.AddInt32(&dials, 1)
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case <-c.Messages(): //asked for once Run started
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for messages")
+	}
+	cancel()
+	<-done
+	if atomic.LoadInt32(&dials) == 0 || atomic.LoadInt32(&tunnels) == 0 {
+		t.Errorf("transport not used: %d dials, %d tunnels", dials, tunnels)
+	}
+}
@@ -0,0 +1,130 @@
+package taurosapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// wsSession keeps one websocket connection alive: it dials, lets onConnect
+// (re)subscribe, feeds every message to onMessage, pings the server and
+// reconnects with backoff when the connection drops or goes stale
+type wsSession struct {
+	url          string
+	transport    *http.Transport             // dialer, proxy and TLS settings, nil for those of http.DefaultTransport
+	header       func() (http.Header, error) // built again on each dial, e.g. to sign a fresh nonce
+	onConnect    func(ctx context.Context, c *wsConn) error
+	onMessage    func(data []byte) error
+	pingInterval time.Duration
+	staleTimeout time.Duration // reconnect when nothing is received for this long
+	backoff      RetryPolicy   // MaxAttempts is ignored, the session retries until ctx is done
+	logger       Logger
+}
+
+// Defaults of the websocket connections of WsClient and MarketStream
+const (
+	wsDefaultPingInterval = 15 * time.Second
+	wsDefaultStaleTimeout = 45 * time.Second
+	wsDefaultBaseDelay    = time.Second
+	wsDefaultMaxDelay     = 30 * time.Second
+)
+
+// setDefaults replaces the unset or negative settings with the defaults
+func (s *wsSession) setDefaults() {
+	if s.pingInterval <= 0 {
+		s.pingInterval = wsDefaultPingInterval
+	}
+	if s.staleTimeout <= 0 {
+		s.staleTimeout = wsDefaultStaleTimeout
+	}
+	if s.backoff.BaseDelay <= 0 {
+		s.backoff.BaseDelay = wsDefaultBaseDelay
+	}
+	if s.backoff.MaxDelay <= 0 {
+		s.backoff.MaxDelay = wsDefaultMaxDelay
+	}
+}
+
+// run blocks until ctx is done and returns ctx.Err()
+func (s *wsSession) run(ctx context.Context) error {
+	s.setDefaults()
+	for retry := 1; ; retry++ {
+		connected, err := s.connectAndServe(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if connected {
+			retry = 1
+		}
+		wait := s.backoff.backoff(retry, 0)
+		s.logf("tauros websocket %s: %v, reconnecting in %s", s.url, err, wait)
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// connectAndServe returns when the connection is lost, connected reports
+// whether the subscription succeeded so the backoff can be reset
+func (s *wsSession) connectAndServe(ctx context.Context) (connected bool, err error) {
+	var header http.Header
+	if s.header != nil {
+		if header, err = s.header(); err != nil {
+			return false, err
+		}
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	conn, err := dialWebsocket(dialCtx, s.transport, s.url, header)
+	cancel()
+	if err != nil {
+		return false, err
+	}
+	conn.readTimeout = s.staleTimeout
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(s.pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				conn.Close()
+				return
+			case <-done:
+				conn.Close()
+				return
+			case <-ticker.C:
+				if err := conn.WriteMessage(wsOpPing, nil); err != nil {
+					conn.conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	if s.onConnect != nil {
+		if err := s.onConnect(ctx, conn); err != nil {
+			return false, fmt.Errorf("subscribe: %w", err)
+		}
+	}
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if isWsTimeout(err) {
+				return true, fmt.Errorf("stale connection, nothing received for %s", s.staleTimeout)
+			}
+			return true, err
+		}
+		if err := s.onMessage(data); err != nil {
+			s.logf("tauros websocket %s: %v", s.url, err)
+		}
+	}
+}
+
+func (s *wsSession) logf(format string, v ...interface{}) {
+	if s.logger != nil {
+		s.logger.Printf(format, v...)
+	}
+}